@@ -0,0 +1,316 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Autenticação opcional por chave de API e limite de requisições por chave.
+//
+// As chaves ficam em api_keys.json (ou API_KEYS_CONFIG) apenas como hash SHA-256; sem o
+// arquivo a API continua aberta, como antes. Cada chave tem escopos: "read" para os
+// endpoints de consulta e "admin" para /admin/*. A chave é enviada em X-API-Key,
+// Authorization: Bearer ou ?api_key= (para leitores de feed e EventSource, que não enviam
+// cabeçalhos; evite quando possível: o log de acesso omite o valor, mas proxies podem gravá-lo).
+// Cada chave tem um token bucket próprio; sem tokens a resposta é 429 com Retry-After.
+
+const (
+	defaultAPIKeysFilename = "api_keys.json"
+	defaultAPIRateLimit    = 10 // requisições por segundo
+	defaultAPIRateBurst    = 20
+	// apiKeyHashPrefix identifica o algoritmo do hash em api_keys.json.
+	apiKeyHashPrefix = "sha256:"
+)
+
+// Escopos de chave
+const (
+	ScopeRead  = "read"
+	ScopeAdmin = "admin"
+)
+
+var allScopes = []string{ScopeRead, ScopeAdmin}
+
+// APIKey é uma entrada de api_keys.json.
+type APIKey struct {
+	ID     string   `json:"id"`
+	Hash   string   `json:"hash"` // "sha256:<hex>" da chave
+	Scopes []string `json:"scopes"`
+	// RateLimit (req/s) e Burst sobrescrevem API_RATE_LIMIT e API_RATE_BURST para esta chave.
+	RateLimit float64 `json:"rate_limit,omitempty"`
+	Burst     int     `json:"burst,omitempty"`
+}
+
+// apiKeysFilename permite trocar o arquivo pela variável API_KEYS_CONFIG.
+func apiKeysFilename() string {
+	if name := os.Getenv("API_KEYS_CONFIG"); name != "" {
+		return name
+	}
+	return defaultAPIKeysFilename
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return apiKeyHashPrefix + hex.EncodeToString(sum[:])
+}
+
+// newAPIKey gera uma chave aleatória de 32 bytes (hex), usada por -mode=keygen.
+func newAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "ccr_" + hex.EncodeToString(b), nil
+}
+
+// parseScopes interpreta "read,admin".
+func parseScopes(spec string) ([]string, error) {
+	var scopes []string
+	for _, s := range strings.Split(spec, ",") {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" {
+			continue
+		}
+		if !slices.Contains(allScopes, s) {
+			return nil, fmt.Errorf("escopo desconhecido %q (use %s)", s, strings.Join(allScopes, ", "))
+		}
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("informe ao menos um escopo")
+	}
+	return scopes, nil
+}
+
+// loadAPIKeys lê as chaves. Arquivo inexistente significa autenticação desativada (nil).
+func loadAPIKeys(filename string) ([]APIKey, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("JSON inválido: %w", err)
+	}
+	ids := make(map[string]bool)
+	hashes := make(map[string]bool)
+	for i, k := range keys {
+		if k.ID == "" {
+			return nil, fmt.Errorf("chave %d sem id", i+1)
+		}
+		if ids[k.ID] {
+			return nil, fmt.Errorf("id de chave repetido: %s", k.ID)
+		}
+		ids[k.ID] = true
+		hexPart, ok := strings.CutPrefix(k.Hash, apiKeyHashPrefix)
+		if b, err := hex.DecodeString(hexPart); !ok || err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("chave %s: hash inválido (use \"%s<hex>\", gerado por -mode=keygen)", k.ID, apiKeyHashPrefix)
+		}
+		keys[i].Hash = strings.ToLower(k.Hash)
+		if hashes[keys[i].Hash] {
+			return nil, fmt.Errorf("chave %s: hash repetido", k.ID)
+		}
+		hashes[keys[i].Hash] = true
+		if _, err := parseScopes(strings.Join(k.Scopes, ",")); err != nil {
+			return nil, fmt.Errorf("chave %s: %w", k.ID, err)
+		}
+		if k.RateLimit < 0 || k.Burst < 0 {
+			return nil, fmt.Errorf("chave %s: rate_limit e burst não podem ser negativos", k.ID)
+		}
+	}
+	return keys, nil
+}
+
+// apiRateDefaults lê API_RATE_LIMIT (req/s) e API_RATE_BURST.
+func apiRateDefaults() (float64, int) {
+	rate := float64(defaultAPIRateLimit)
+	if val, err := strconv.ParseFloat(os.Getenv("API_RATE_LIMIT"), 64); err == nil && val > 0 {
+		rate = val
+	}
+	burst := defaultAPIRateBurst
+	if val, err := strconv.Atoi(os.Getenv("API_RATE_BURST")); err == nil && val > 0 {
+		burst = val
+	}
+	return rate, burst
+}
+
+// tokenBucket acumula rate tokens por segundo até burst; cada requisição consome um.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// take consome um token. Sem tokens, retorna quanto esperar até o próximo.
+func (b *tokenBucket) take(now time.Time) (bool, time.Duration) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	return false, wait
+}
+
+// apiAuth valida as chaves e aplica os limites. Um *apiAuth nil deixa tudo passar.
+type apiAuth struct {
+	byHash map[string]APIKey
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket // por ID da chave
+}
+
+func newAPIAuth(keys []APIKey) *apiAuth {
+	if len(keys) == 0 {
+		return nil
+	}
+	rate, burst := apiRateDefaults()
+	a := &apiAuth{byHash: make(map[string]APIKey, len(keys)), buckets: make(map[string]*tokenBucket, len(keys))}
+	now := time.Now()
+	for _, k := range keys {
+		a.byHash[k.Hash] = k
+		b := &tokenBucket{rate: rate, burst: float64(burst), last: now}
+		if k.RateLimit > 0 {
+			b.rate = k.RateLimit
+		}
+		if k.Burst > 0 {
+			b.burst = float64(k.Burst)
+		}
+		b.tokens = b.burst
+		a.buckets[k.ID] = b
+	}
+	return a
+}
+
+// requestAPIKey extrai a chave do cabeçalho X-API-Key, do Authorization: Bearer ou de ?api_key=.
+func requestAPIKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return c.Query("api_key")
+}
+
+// redactedLogFormatter é o formato padrão do log de acesso do Gin, com o valor de ?api_key=
+// trocado por REDACTED para que as chaves não fiquem gravadas nos logs.
+func redactedLogFormatter(param gin.LogFormatterParams) string {
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor = param.StatusCodeColor()
+		methodColor = param.MethodColor()
+		resetColor = param.ResetColor()
+	}
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		redactAPIKeyQuery(param.Path),
+		param.ErrorMessage,
+	)
+}
+
+// redactAPIKeyQuery troca o valor de api_key na query string, mantendo os demais parâmetros
+// na ordem original.
+func redactAPIKeyQuery(path string) string {
+	base, query, ok := strings.Cut(path, "?")
+	if !ok {
+		return path
+	}
+	params := strings.Split(query, "&")
+	for i, p := range params {
+		name, _, _ := strings.Cut(p, "=")
+		if key, err := url.QueryUnescape(name); err == nil && key == "api_key" {
+			params[i] = name + "=REDACTED"
+		}
+	}
+	return base + "?" + strings.Join(params, "&")
+}
+
+// requiredScope é o escopo exigido pela rota; "" para rotas públicas (documentação).
+func requiredScope(path string) string {
+	switch {
+	case strings.HasPrefix(path, "/swagger/"):
+		return ""
+	case path == "/admin" || strings.HasPrefix(path, "/admin/"):
+		return ScopeAdmin
+	default:
+		return ScopeRead
+	}
+}
+
+// Middleware autentica, confere o escopo e consome um token do bucket da chave.
+func (a *apiAuth) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a == nil {
+			c.Next()
+			return
+		}
+		scope := requiredScope(c.Request.URL.Path)
+		if scope == "" {
+			c.Next()
+			return
+		}
+
+		key := requestAPIKey(c)
+		if key == "" {
+			c.Header("WWW-Authenticate", `Bearer realm="copom-crawler"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: "Chave de API ausente (use o cabeçalho X-API-Key)."})
+			return
+		}
+		apiKey, ok := a.byHash[hashAPIKey(key)]
+		if !ok {
+			c.Header("WWW-Authenticate", `Bearer realm="copom-crawler", error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: "Chave de API inválida."})
+			return
+		}
+		if !slices.Contains(apiKey.Scopes, scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Error: fmt.Sprintf("A chave %s não tem o escopo '%s'.", apiKey.ID, scope)})
+			return
+		}
+
+		a.mu.Lock()
+		bucket := a.buckets[apiKey.ID]
+		allowed, wait := bucket.take(time.Now())
+		remaining := int(bucket.tokens)
+		a.mu.Unlock()
+		c.Header("X-RateLimit-Limit", strconv.FormatFloat(bucket.rate, 'f', -1, 64))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if !allowed {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, ErrorResponse{Error: fmt.Sprintf("Limite de requisições excedido. Tente novamente em %d s.", retryAfter)})
+			return
+		}
+
+		c.Set("api_key_id", apiKey.ID)
+		c.Next()
+	}
+}
@@ -0,0 +1,552 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Gráficos do dataset bruto renderizados em SVG, servidos em GET /charts/{nome}.svg e
+// gravados por -mode=charts. Reproduzem os oito gráficos do antigo graficos_dataset.py
+// (matplotlib), calculados sobre os mesmos dados servidos pela API.
+
+// chartRecord é uma ata com data válida, pronta para os gráficos.
+type chartRecord struct {
+	Date    time.Time
+	Meeting int
+	Dollar  float64
+	IPCA    float64
+	Size    int
+}
+
+// chartData é a entrada de todos os gráficos: registros em ordem cronológica e as atas
+// originais do período (para a completude).
+type chartData struct {
+	Records []chartRecord
+	Atas    []CopomAta
+}
+
+// ChartInfo descreve um gráfico disponível (GET /charts).
+type ChartInfo struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+}
+
+type chartSpec struct {
+	Name        string
+	Title       string
+	Description string
+	render      func(d chartData, title string) string
+}
+
+var chartSpecs = []chartSpec{
+	{"evolucao-dolar", "Evolução do Dólar PTAX nas datas das reuniões do COPOM", "Linha do dólar com máximo e mínimo do período", chartDollarEvolution},
+	{"evolucao-ipca", "IPCA mensal nas datas das reuniões do COPOM", "Barras do IPCA coloridas por faixa, com referência de 0,5% ao mês", chartIPCAEvolution},
+	{"atas-por-ano", "Quantidade de reuniões do COPOM por ano", "Atas por ano, destacando anos sem dados", chartAtasPerYear},
+	{"correlacao-dolar-ipca", "Correlação entre Dólar e IPCA", "Dispersão dólar × IPCA colorida por período, com tendência linear", chartDollarIPCACorrelation},
+	{"tamanho-atas", "Evolução do tamanho das atas do COPOM", "Tamanho do texto das atas com média móvel de 10 atas", chartAtaSize},
+	{"boxplot-ipca-decada", "Distribuição do IPCA por década", "Boxplot do IPCA agrupado por década", chartIPCABoxplot},
+	{"dolar-ipca-dual", "Evolução comparativa: Dólar PTAX vs IPCA", "Dólar e IPCA em eixos y independentes", chartDollarIPCADual},
+	{"completude", "Completude dos dados por campo", "Percentual de atas com cada campo preenchido", chartCompleteness},
+}
+
+func findChart(name string) (chartSpec, bool) {
+	for _, spec := range chartSpecs {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return chartSpec{}, false
+}
+
+func chartNames() []string {
+	names := make([]string, len(chartSpecs))
+	for i, spec := range chartSpecs {
+		names[i] = spec.Name
+	}
+	return names
+}
+
+// buildChartData filtra as atas pelo intervalo de datas (YYYY-MM-DD, inclusivo) e
+// monta os registros em ordem cronológica.
+func buildChartData(atas []CopomAta, from, to string) chartData {
+	var d chartData
+	for _, ata := range atas {
+		if (from != "" && ata.DataReuniao < from) || (to != "" && ata.DataReuniao > to) {
+			continue
+		}
+		d.Atas = append(d.Atas, ata)
+		date, err := time.Parse("2006-01-02", ata.DataReuniao)
+		if err != nil {
+			continue
+		}
+		d.Records = append(d.Records, chartRecord{
+			Date:    date,
+			Meeting: ata.NumeroReuniao,
+			Dollar:  ata.ValorDolar,
+			IPCA:    ata.ValorIPCA,
+			Size:    len([]rune(ata.Conteudo)),
+		})
+	}
+	slices.SortFunc(d.Records, func(a, b chartRecord) int { return a.Date.Compare(b.Date) })
+	return d
+}
+
+// renderChart gera o SVG do gráfico, acrescentando o período dos dados ao título.
+func renderChart(spec chartSpec, d chartData) string {
+	title := spec.Title
+	if n := len(d.Records); n > 0 {
+		title = fmt.Sprintf("%s (%d-%d)", title, d.Records[0].Date.Year(), d.Records[n-1].Date.Year())
+	}
+	return spec.render(d, title)
+}
+
+// formatFixed formata com vírgula decimal; valores que arredondam para zero saem sem sinal.
+func formatFixed(decimals int) func(float64) string {
+	return func(v float64) string {
+		if math.Abs(v) < 0.5*math.Pow(10, -float64(decimals)) {
+			v = 0
+		}
+		return strings.Replace(fmt.Sprintf("%.*f", decimals, v), ".", ",", 1)
+	}
+}
+
+// chartPalette é usada para categorias (períodos, décadas).
+var chartPalette = []string{"#d62728", "#ff7f0e", "#2ca02c", "#1f77b4", "#9467bd", "#8c564b", "#e377c2", "#17becf"}
+
+// 1. Evolução do dólar
+func chartDollarEvolution(d chartData, title string) string {
+	var recs []chartRecord
+	for _, r := range d.Records {
+		if r.Dollar > 0 {
+			recs = append(recs, r)
+		}
+	}
+	if len(recs) == 0 {
+		return emptyChart(title)
+	}
+
+	p := newPlotFrame(chartWidth, chartHeight, 30, title, "Data", "Valor do Dólar (R$)")
+	first, last := recs[0].Date, recs[len(recs)-1].Date
+	p.setX(dayNumber(first), dayNumber(last))
+	values := make([]float64, len(recs))
+	for i, r := range recs {
+		values[i] = r.Dollar
+	}
+	lo, hi := paddedRange(values...)
+	p.setY(lo, hi)
+	p.yAxis(formatFixed(2))
+	p.xYearAxis(first, last)
+
+	line := make([]svgPoint, len(recs))
+	for i, r := range recs {
+		line[i] = svgPoint{p.x.at(dayNumber(r.Date)), p.y.at(r.Dollar)}
+	}
+	area := append([]svgPoint{{line[0].X, p.bottom}}, line...)
+	area = append(area, svgPoint{line[len(line)-1].X, p.bottom})
+	p.svg.polygon(area, "#1f77b4", 0.3)
+	p.svg.polyline(line, "#1f77b4", 1.5, "")
+
+	minV, maxV := slices.Min(values), slices.Max(values)
+	p.hline(maxV, "#d62728", "6,4")
+	p.hline(minV, "#2ca02c", "6,4")
+	p.legend([]legendItem{
+		{Label: "Máximo: R$" + formatFixed(2)(maxV), Color: "#d62728", Dash: "6,4"},
+		{Label: "Mínimo: R$" + formatFixed(2)(minV), Color: "#2ca02c", Dash: "6,4"},
+	}, false)
+	return p.svg.String()
+}
+
+// ipcaColor segue as faixas do gráfico original: >1% vermelho, >0,5% laranja,
+// ≥0 verde e negativo azul.
+func ipcaColor(v float64) string {
+	switch {
+	case v > 1.0:
+		return "#d62728"
+	case v > 0.5:
+		return "#ff7f0e"
+	case v >= 0:
+		return "#2ca02c"
+	default:
+		return "#1f77b4"
+	}
+}
+
+// 2. Evolução do IPCA
+func chartIPCAEvolution(d chartData, title string) string {
+	var recs []chartRecord
+	for _, r := range d.Records {
+		if r.IPCA != 0 {
+			recs = append(recs, r)
+		}
+	}
+	if len(recs) == 0 {
+		return emptyChart(title)
+	}
+
+	p := newPlotFrame(chartWidth, chartHeight, 30, title, "Data", "IPCA (%)")
+	first, last := recs[0].Date, recs[len(recs)-1].Date
+	// Meio mês de folga para as barras das pontas
+	p.setX(dayNumber(first)-15, dayNumber(last)+15)
+	values := []float64{0, 0.5}
+	for _, r := range recs {
+		values = append(values, r.IPCA)
+	}
+	lo, hi := paddedRange(values...)
+	p.setY(lo, hi)
+	p.yAxis(formatFixed(2))
+	p.xYearAxis(first, last)
+
+	barWidth := math.Max(2, p.x.at(20)-p.x.at(0))
+	zero := p.y.at(0)
+	for _, r := range recs {
+		x, y := p.x.at(dayNumber(r.Date)), p.y.at(r.IPCA)
+		p.svg.rect(x-barWidth/2, math.Min(y, zero), barWidth, math.Abs(zero-y), ipcaColor(r.IPCA), "none", 0.7)
+	}
+	p.hline(0, "black", "")
+	p.hline(0.5, "#ff7f0e", "6,4")
+	p.legend([]legendItem{{Label: "Meta aproximada (0,5%/mês)", Color: "#ff7f0e", Dash: "6,4"}}, true)
+	return p.svg.String()
+}
+
+// 3. Atas por ano
+func chartAtasPerYear(d chartData, title string) string {
+	if len(d.Records) == 0 {
+		return emptyChart(title)
+	}
+	counts := make(map[int]int)
+	for _, r := range d.Records {
+		counts[r.Date.Year()]++
+	}
+	firstYear, lastYear := d.Records[0].Date.Year(), d.Records[len(d.Records)-1].Date.Year()
+	nYears := lastYear - firstYear + 1
+
+	p := newPlotFrame(chartWidth, chartHeight, 30, title, "Ano", "Número de atas")
+	p.setX(0, float64(nYears))
+	maxCount := 0
+	for _, n := range counts {
+		maxCount = max(maxCount, n)
+	}
+	p.setY(0, float64(maxCount)*1.15)
+	p.yAxis(formatFixed(0))
+
+	slot := p.x.at(1) - p.x.at(0)
+	hasGap := false
+	for i := range nYears {
+		year := firstYear + i
+		x := p.x.at(float64(i))
+		p.svg.text(x+slot/2, p.bottom+18, fmt.Sprint(year), "end", 11, "#444", -45)
+		n := counts[year]
+		if n == 0 {
+			// Anos sem nenhuma ata no período indicam lacuna no dataset
+			hasGap = true
+			p.svg.rect(x, p.top, slot, p.bottom-p.top, "#d62728", "none", 0.2)
+			continue
+		}
+		// Tons de azul crescentes ao longo do tempo, como o colormap Blues do original
+		shade := 0.4 + 0.5*float64(i)/float64(max(nYears-1, 1))
+		fill := fmt.Sprintf("rgb(%d,%d,%d)", int(247-200*shade), int(251-150*shade), int(255-100*shade))
+		y := p.y.at(float64(n))
+		p.svg.rect(x+slot*0.1, y, slot*0.8, p.bottom-y, fill, "#08306b", 1)
+		p.svg.text(x+slot/2, y-4, fmt.Sprint(n), "middle", 10, "#222", 0)
+	}
+	if hasGap {
+		p.legend([]legendItem{{Label: "Gap nos dados", Color: "#d62728", Box: true}}, true)
+	}
+	return p.svg.String()
+}
+
+// linearFit retorna inclinação e intercepto da regressão de ys em xs (mínimos quadrados).
+func linearFit(xs, ys []float64) (float64, float64) {
+	n := float64(len(xs))
+	var sx, sy, sxx, sxy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		sxy += xs[i] * ys[i]
+	}
+	den := n*sxx - sx*sx
+	if den == 0 {
+		return 0, sy / n
+	}
+	slope := (n*sxy - sx*sy) / den
+	return slope, (sy - slope*sx) / n
+}
+
+// 4. Correlação dólar × IPCA
+func chartDollarIPCACorrelation(d chartData, title string) string {
+	var xs, ys []float64
+	var periods []int
+	for _, r := range d.Records {
+		if r.Dollar > 0 && r.IPCA != 0 {
+			xs = append(xs, r.Dollar)
+			ys = append(ys, r.IPCA)
+			periods = append(periods, r.Date.Year()-r.Date.Year()%5)
+		}
+	}
+	if len(xs) == 0 {
+		return emptyChart(title)
+	}
+
+	p := newPlotFrame(chartWidth, 650, 30, title+" — cor indica o período", "Valor do Dólar (R$)", "IPCA (%)")
+	xlo, xhi := paddedRange(xs...)
+	ylo, yhi := paddedRange(ys...)
+	p.setX(xlo, xhi)
+	p.setY(ylo, yhi)
+	p.yAxis(formatFixed(2))
+	p.xValueAxis(formatFixed(2))
+
+	// Períodos de 5 anos, cada um com uma cor da paleta
+	distinct := slices.Compact(slices.Sorted(slices.Values(periods)))
+	colorOf := func(period int) string {
+		return chartPalette[slices.Index(distinct, period)%len(chartPalette)]
+	}
+	for i := range xs {
+		p.svg.circle(p.x.at(xs[i]), p.y.at(ys[i]), 5, colorOf(periods[i]), 0.6)
+	}
+
+	legend := make([]legendItem, 0, len(distinct)+1)
+	for _, period := range distinct {
+		legend = append(legend, legendItem{Label: fmt.Sprintf("%d-%d", period, period+4), Color: colorOf(period), Box: true})
+	}
+	if len(xs) > 1 {
+		slope, intercept := linearFit(xs, ys)
+		lo, hi := slices.Min(xs), slices.Max(xs)
+		p.svg.line(p.x.at(lo), p.y.at(slope*lo+intercept), p.x.at(hi), p.y.at(slope*hi+intercept), "black", 1.2, "6,4")
+		legend = append(legend, legendItem{Label: "Tendência linear", Color: "black", Dash: "6,4"})
+	}
+	p.legend(legend, true)
+
+	corr := 0.0
+	if r := pearson(xs, ys); r != nil {
+		corr = *r
+	}
+	p.svg.rect(p.left+10, p.top+10, 150, 24, "#f5deb3", "#c8a96e", 0.6)
+	p.svg.text(p.left+18, p.top+27, "Correlação: "+formatFixed(3)(corr), "start", 12, "#222", 0)
+	return p.svg.String()
+}
+
+// 5. Tamanho das atas
+func chartAtaSize(d chartData, title string) string {
+	if len(d.Records) == 0 {
+		return emptyChart(title)
+	}
+	const window = 10
+
+	p := newPlotFrame(chartWidth, chartHeight, 30, title, "Data", "Tamanho (milhares de caracteres)")
+	first, last := d.Records[0].Date, d.Records[len(d.Records)-1].Date
+	p.setX(dayNumber(first), dayNumber(last))
+	sizes := make([]float64, len(d.Records))
+	for i, r := range d.Records {
+		sizes[i] = float64(r.Size) / 1000
+	}
+	lo, hi := paddedRange(sizes...)
+	p.setY(math.Max(lo, 0), hi)
+	p.yAxis(formatFixed(1))
+	p.xYearAxis(first, last)
+
+	for i, r := range d.Records {
+		p.svg.circle(p.x.at(dayNumber(r.Date)), p.y.at(sizes[i]), 4, "steelblue", 0.6)
+	}
+	// Média das 10 atas anteriores, plotada na data da ata seguinte (como no original)
+	var legend []legendItem
+	if len(sizes) > window {
+		var line []svgPoint
+		for i := window; i < len(sizes); i++ {
+			sum := 0.0
+			for _, v := range sizes[i-window : i] {
+				sum += v
+			}
+			line = append(line, svgPoint{p.x.at(dayNumber(d.Records[i].Date)), p.y.at(sum / window)})
+		}
+		p.svg.polyline(line, "#d62728", 2, "")
+		legend = append(legend, legendItem{Label: fmt.Sprintf("Média móvel (%d atas)", window), Color: "#d62728"})
+	}
+	p.legend(legend, false)
+	return p.svg.String()
+}
+
+// quantile usa interpolação linear entre as posições, como o numpy/matplotlib.
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	i := int(pos)
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (pos-float64(i))*(sorted[i+1]-sorted[i])
+}
+
+// 6. Boxplot do IPCA por década
+func chartIPCABoxplot(d chartData, title string) string {
+	groups := make(map[int][]float64)
+	for _, r := range d.Records {
+		if r.IPCA != 0 {
+			decade := r.Date.Year() - r.Date.Year()%10
+			groups[decade] = append(groups[decade], r.IPCA)
+		}
+	}
+	if len(groups) == 0 {
+		return emptyChart(title)
+	}
+	decades := make([]int, 0, len(groups))
+	var all []float64
+	for decade, values := range groups {
+		decades = append(decades, decade)
+		all = append(all, values...)
+	}
+	slices.Sort(decades)
+
+	p := newPlotFrame(chartWidth, chartHeight, 30, title, "Década", "IPCA (%)")
+	p.setX(0, float64(len(decades)))
+	lo, hi := paddedRange(append(all, 0)...)
+	p.setY(lo, hi)
+	p.yAxis(formatFixed(2))
+	p.hline(0, "black", "")
+
+	slot := p.x.at(1) - p.x.at(0)
+	boxWidth := math.Min(slot*0.5, 120)
+	for i, decade := range decades {
+		values := slices.Sorted(slices.Values(groups[decade]))
+		q1, med, q3 := quantile(values, 0.25), quantile(values, 0.5), quantile(values, 0.75)
+		iqr := q3 - q1
+		// Bigodes até o dado mais extremo dentro de 1,5 × IQR; o resto é outlier
+		wLo, wHi := q3, q1
+		for _, v := range values {
+			if v >= q1-1.5*iqr {
+				wLo = math.Min(wLo, v)
+			}
+			if v <= q3+1.5*iqr {
+				wHi = math.Max(wHi, v)
+			}
+		}
+
+		cx := p.x.at(float64(i)) + slot/2
+		color := chartPalette[(i+2)%len(chartPalette)]
+		p.svg.line(cx, p.y.at(wLo), cx, p.y.at(q1), "#333", 1, "")
+		p.svg.line(cx, p.y.at(q3), cx, p.y.at(wHi), "#333", 1, "")
+		p.svg.line(cx-boxWidth/4, p.y.at(wLo), cx+boxWidth/4, p.y.at(wLo), "#333", 1, "")
+		p.svg.line(cx-boxWidth/4, p.y.at(wHi), cx+boxWidth/4, p.y.at(wHi), "#333", 1, "")
+		p.svg.rect(cx-boxWidth/2, p.y.at(q3), boxWidth, p.y.at(q1)-p.y.at(q3), color, "#333", 0.5)
+		p.svg.line(cx-boxWidth/2, p.y.at(med), cx+boxWidth/2, p.y.at(med), "#ff7f0e", 2, "")
+		for _, v := range values {
+			if v < wLo || v > wHi {
+				p.svg.circle(cx, p.y.at(v), 3, "#333", 0.8)
+			}
+		}
+		p.svg.text(cx, p.bottom+18, fmt.Sprintf("%ds (n=%d)", decade, len(values)), "middle", 11, "#444", 0)
+	}
+	return p.svg.String()
+}
+
+// 7. Dólar e IPCA em eixos duplos
+func chartDollarIPCADual(d chartData, title string) string {
+	var recs []chartRecord
+	for _, r := range d.Records {
+		if r.Dollar > 0 && r.IPCA != 0 {
+			recs = append(recs, r)
+		}
+	}
+	if len(recs) == 0 {
+		return emptyChart(title)
+	}
+	const dollarColor, ipcaLineColor = "#1f77b4", "#d62728"
+
+	p := newPlotFrame(chartWidth, chartHeight, 75, title, "Data", "")
+	p.svg.text(18, (p.top+p.bottom)/2, "Dólar (R$)", "middle", 12, dollarColor, -90)
+	first, last := recs[0].Date, recs[len(recs)-1].Date
+	p.setX(dayNumber(first), dayNumber(last))
+
+	dollars := make([]float64, len(recs))
+	ipcas := make([]float64, len(recs))
+	for i, r := range recs {
+		dollars[i], ipcas[i] = r.Dollar, r.IPCA
+	}
+	lo, hi := paddedRange(dollars...)
+	p.setY(lo, hi)
+	p.yAxis(formatFixed(2))
+	p.xYearAxis(first, last)
+	ilo, ihi := paddedRange(append(ipcas, 0)...)
+	ipcaScale := linearScale{ilo, ihi, p.bottom, p.top}
+	p.secondaryYAxis(ipcaScale, "IPCA (%)", ipcaLineColor, formatFixed(2))
+
+	dollarLine := make([]svgPoint, len(recs))
+	ipcaLine := make([]svgPoint, len(recs))
+	for i, r := range recs {
+		x := p.x.at(dayNumber(r.Date))
+		dollarLine[i] = svgPoint{x, p.y.at(r.Dollar)}
+		ipcaLine[i] = svgPoint{x, ipcaScale.at(r.IPCA)}
+	}
+	area := append([]svgPoint{{dollarLine[0].X, p.bottom}}, dollarLine...)
+	area = append(area, svgPoint{dollarLine[len(dollarLine)-1].X, p.bottom})
+	p.svg.polygon(area, dollarColor, 0.2)
+	p.svg.polyline(dollarLine, dollarColor, 1.5, "")
+	p.svg.line(p.left, ipcaScale.at(0), p.right, ipcaScale.at(0), "gray", 0.5, "6,4")
+	p.svg.polyline(ipcaLine, ipcaLineColor, 1.5, "")
+	p.legend([]legendItem{
+		{Label: "Dólar PTAX", Color: dollarColor},
+		{Label: "IPCA", Color: ipcaLineColor},
+	}, false)
+	return p.svg.String()
+}
+
+// 8. Completude por campo (mesmos critérios de /stats)
+func chartCompleteness(d chartData, title string) string {
+	if len(d.Atas) == 0 {
+		return emptyChart(title)
+	}
+	fields := computeAtasStats(d.Atas).Completeness
+
+	p := newPlotFrame(chartWidth, chartHeight, 30, title, "Porcentagem de registros preenchidos (%)", "")
+	p.left = 140
+	p.setX(0, 105)
+	p.setY(0, float64(len(fields)))
+	for _, v := range niceTicks(0, 100, 5) {
+		x := p.x.at(v)
+		p.svg.line(x, p.top, x, p.bottom, "#e5e5e5", 1, "")
+		p.svg.text(x, p.bottom+18, formatFixed(0)(v), "middle", 11, "#444", 0)
+	}
+	p.svg.line(p.left, p.bottom, p.right, p.bottom, "#888", 1, "")
+	p.svg.line(p.left, p.top, p.left, p.bottom, "#888", 1, "")
+
+	slot := p.y.at(0) - p.y.at(1)
+	for i, fc := range fields {
+		// Primeiro campo no topo, como o barh do matplotlib com a lista invertida
+		y := p.y.at(float64(len(fields) - i))
+		color := "#d62728"
+		switch {
+		case fc.Percent >= 95:
+			color = "#2ca02c"
+		case fc.Percent >= 80:
+			color = "#ff7f0e"
+		}
+		p.svg.rect(p.left, y+slot*0.1, p.x.at(fc.Percent)-p.left, slot*0.8, color, "darkgray", 1)
+		p.svg.text(p.left-8, y+slot/2+4, fc.Field, "end", 11, "#333", 0)
+		p.svg.text(p.x.at(fc.Percent)+5, y+slot/2+4, formatFixed(1)(fc.Percent)+"%", "start", 11, "#222", 0)
+	}
+	for _, ref := range []struct {
+		value float64
+		color string
+	}{{95, "#2ca02c"}, {80, "#ff7f0e"}} {
+		x := p.x.at(ref.value)
+		p.svg.line(x, p.top, x, p.bottom, ref.color, 1, "6,4")
+	}
+	p.legend([]legendItem{
+		{Label: "95% (excelente)", Color: "#2ca02c", Dash: "6,4"},
+		{Label: "80% (bom)", Color: "#ff7f0e", Dash: "6,4"},
+	}, true)
+	return p.svg.String()
+}
+
+// chartList monta a listagem de GET /charts.
+func chartList() []ChartInfo {
+	list := make([]ChartInfo, len(chartSpecs))
+	for i, spec := range chartSpecs {
+		list[i] = ChartInfo{Name: spec.Name, Title: spec.Title, Description: spec.Description, URL: "/charts/" + spec.Name + ".svg"}
+	}
+	return list
+}
@@ -0,0 +1,459 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Diff entre duas atas: os parágrafos numerados ("1.", "2.", ...) são alinhados por
+// similaridade de conteúdo — a numeração muda quando o Comitê insere ou remove um
+// parágrafo — e, dentro dos parágrafos alterados, as frases são comparadas uma a uma.
+
+const (
+	diffEqual     = "equal"
+	diffAdded     = "added"
+	diffRemoved   = "removed"
+	diffChanged   = "changed"
+	diffUnchanged = "unchanged"
+)
+
+const (
+	// Similaridade mínima (Jaccard dos termos) para considerar dois parágrafos o mesmo
+	paragraphMatchThreshold = 0.35
+	// ... e para tratar uma frase removida + uma adicionada como alteração
+	sentenceMatchThreshold = 0.3
+)
+
+// AtaRef identifica uma das atas comparadas.
+type AtaRef struct {
+	NumeroReuniao int    `json:"numero_reuniao"`
+	DataReuniao   string `json:"data_reuniao"`
+	URL           string `json:"url"`
+}
+
+// SentenceDiff é uma operação em nível de frase. Old/New vêm preenchidos conforme Op.
+type SentenceDiff struct {
+	Op  string `json:"op"`
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// ParagraphDiff é um parágrafo alinhado entre as atas. OldNumber/NewNumber são a numeração
+// em cada ata (0 quando o parágrafo não existe nela).
+type ParagraphDiff struct {
+	Status     string         `json:"status"`
+	OldNumber  int            `json:"old_number,omitempty"`
+	NewNumber  int            `json:"new_number,omitempty"`
+	Similarity float64        `json:"similarity,omitempty"`
+	Text       string         `json:"text,omitempty"`
+	Sentences  []SentenceDiff `json:"sentences,omitempty"`
+}
+
+// DiffSummary conta as diferenças por tipo.
+type DiffSummary struct {
+	ParagraphsAdded     int `json:"paragraphs_added"`
+	ParagraphsRemoved   int `json:"paragraphs_removed"`
+	ParagraphsChanged   int `json:"paragraphs_changed"`
+	ParagraphsUnchanged int `json:"paragraphs_unchanged"`
+	SentencesAdded      int `json:"sentences_added"`
+	SentencesRemoved    int `json:"sentences_removed"`
+	SentencesChanged    int `json:"sentences_changed"`
+}
+
+// AtaDiffResponse é o diff de From (ata anterior) para To.
+type AtaDiffResponse struct {
+	From       AtaRef          `json:"from"`
+	To         AtaRef          `json:"to"`
+	Summary    DiffSummary     `json:"summary"`
+	Paragraphs []ParagraphDiff `json:"paragraphs"`
+}
+
+var reParagraphNumber = regexp.MustCompile(`(?:^|\s)(\d{1,3})\.\s`)
+
+// reSectionHeader casa cabeçalhos de seção como "B) Cenários e análise de riscos", que
+// ficam na mesma linha do parágrafo seguinte.
+var reSectionHeader = regexp.MustCompile(`^[A-Z]\)\s`)
+
+// numberedParagraphs extrai os parágrafos numerados da ata, aceitando apenas números em
+// sequência (1, 2, 3...) para não confundir com valores como "2025." no meio do texto.
+// Atas sem numeração (ou HTML mal parseado) caem no mesmo agrupamento do enriquecedor.
+func numberedParagraphs(ata CopomAta) []string {
+	text := ata.Conteudo
+	if ata.FalhaNoParse {
+		text = stripHTML(text)
+	}
+
+	type marker struct{ start, end int }
+	var markers []marker
+	next := 1
+	for _, m := range reParagraphNumber.FindAllStringSubmatchIndex(text, -1) {
+		if text[m[2]:m[3]] != strconv.Itoa(next) {
+			continue
+		}
+		markers = append(markers, marker{m[2], m[1]})
+		next++
+	}
+	if len(markers) < 3 {
+		return splitParagraphs(text)
+	}
+
+	paragraphs := make([]string, len(markers))
+	for i, mk := range markers {
+		end := len(text)
+		if i+1 < len(markers) {
+			end = markers[i+1].start
+		}
+		body := text[mk.end:end]
+		// Descarta o cabeçalho de seção que antecede o próximo número
+		if nl := strings.LastIndex(body, "\n"); nl >= 0 && reSectionHeader.MatchString(strings.TrimSpace(body[nl+1:])) {
+			body = body[:nl]
+		}
+		paragraphs[i] = strings.Join(strings.Fields(body), " ")
+	}
+	return paragraphs
+}
+
+// splitSentences quebra em frases no ponto final (ou ! e ?) seguido de espaço e maiúscula.
+// Decimais em português usam vírgula, então "4,5%" não quebra a frase.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i, r := range text {
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+		rest := text[i+1:]
+		trimmed := strings.TrimLeft(rest, " ")
+		if len(trimmed) == len(rest) || trimmed == "" {
+			continue
+		}
+		if next, _ := utf8.DecodeRuneInString(trimmed); unicode.IsUpper(next) || next == '"' || next == '“' {
+			sentences = append(sentences, strings.TrimSpace(text[start:i+1]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(text[start:]); last != "" {
+		sentences = append(sentences, last)
+	}
+	return sentences
+}
+
+// termSet reaproveita a normalização da busca (acentos, stopwords, stemming).
+func termSet(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, tok := range tokenizeForSearch(text) {
+		set[tok.Term] = true
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	inter := 0
+	for term := range a {
+		if b[term] {
+			inter++
+		}
+	}
+	return float64(inter) / float64(len(a)+len(b)-inter)
+}
+
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// alignParagraphs alinha preservando a ordem (programação dinâmica no estilo
+// Needleman-Wunsch), maximizando a soma das similaridades dos pares aceitos.
+func alignParagraphs(oldParas, newParas []string) []ParagraphDiff {
+	oldTerms := make([]map[string]bool, len(oldParas))
+	for i, p := range oldParas {
+		oldTerms[i] = termSet(p)
+	}
+	newTerms := make([]map[string]bool, len(newParas))
+	for j, p := range newParas {
+		newTerms[j] = termSet(p)
+	}
+
+	n, m := len(oldParas), len(newParas)
+	sim := make([][]float64, n)
+	score := make([][]float64, n+1)
+	for i := range score {
+		score[i] = make([]float64, m+1)
+	}
+	for i := range n {
+		sim[i] = make([]float64, m)
+		for j := range m {
+			sim[i][j] = jaccard(oldTerms[i], newTerms[j])
+		}
+	}
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			best := max(score[i-1][j], score[i][j-1])
+			if s := sim[i-1][j-1]; s >= paragraphMatchThreshold {
+				best = max(best, score[i-1][j-1]+s)
+			}
+			score[i][j] = best
+		}
+	}
+
+	// Reconstrução de trás para frente
+	var reversed []ParagraphDiff
+	i, j := n, m
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && sim[i-1][j-1] >= paragraphMatchThreshold && score[i][j] == score[i-1][j-1]+sim[i-1][j-1]:
+			reversed = append(reversed, diffParagraphPair(oldParas[i-1], newParas[j-1], i, j, sim[i-1][j-1]))
+			i--
+			j--
+		case j > 0 && (i == 0 || score[i][j] == score[i][j-1]):
+			reversed = append(reversed, ParagraphDiff{Status: diffAdded, NewNumber: j, Text: newParas[j-1]})
+			j--
+		default:
+			reversed = append(reversed, ParagraphDiff{Status: diffRemoved, OldNumber: i, Text: oldParas[i-1]})
+			i--
+		}
+	}
+
+	result := make([]ParagraphDiff, len(reversed))
+	for k, d := range reversed {
+		result[len(reversed)-1-k] = d
+	}
+	return result
+}
+
+func diffParagraphPair(oldText, newText string, oldNum, newNum int, similarity float64) ParagraphDiff {
+	d := ParagraphDiff{OldNumber: oldNum, NewNumber: newNum, Similarity: similarity}
+	if normalizeSpace(oldText) == normalizeSpace(newText) {
+		d.Status = diffUnchanged
+		d.Similarity = 0 // omitido: idêntico
+		return d
+	}
+	d.Status = diffChanged
+	d.Sentences = diffSentences(splitSentences(oldText), splitSentences(newText))
+	return d
+}
+
+// diffSentences faz a LCS das frases (comparadas com espaços normalizados) e, em cada
+// trecho divergente, pareia removidas e adicionadas parecidas como "changed".
+func diffSentences(oldS, newS []string) []SentenceDiff {
+	n, m := len(oldS), len(newS)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if normalizeSpace(oldS[i]) == normalizeSpace(newS[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []SentenceDiff
+	var removed, added []string
+	flush := func() {
+		k := 0
+		for ; k < len(removed) && k < len(added); k++ {
+			if jaccard(termSet(removed[k]), termSet(added[k])) >= sentenceMatchThreshold {
+				ops = append(ops, SentenceDiff{Op: diffChanged, Old: removed[k], New: added[k]})
+			} else {
+				ops = append(ops, SentenceDiff{Op: diffRemoved, Old: removed[k]}, SentenceDiff{Op: diffAdded, New: added[k]})
+			}
+		}
+		for _, s := range removed[k:] {
+			ops = append(ops, SentenceDiff{Op: diffRemoved, Old: s})
+		}
+		for _, s := range added[k:] {
+			ops = append(ops, SentenceDiff{Op: diffAdded, New: s})
+		}
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && normalizeSpace(oldS[i]) == normalizeSpace(newS[j]):
+			flush()
+			ops = append(ops, SentenceDiff{Op: diffEqual, Old: oldS[i]})
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+			added = append(added, newS[j])
+			j++
+		default:
+			removed = append(removed, oldS[i])
+			i++
+		}
+	}
+	flush()
+	return ops
+}
+
+func ataRef(ata CopomAta) AtaRef {
+	return AtaRef{NumeroReuniao: ata.NumeroReuniao, DataReuniao: ata.DataReuniao, URL: ata.URL}
+}
+
+// diffAtas compara from (anterior) com to.
+func diffAtas(from, to CopomAta) AtaDiffResponse {
+	resp := AtaDiffResponse{
+		From:       ataRef(from),
+		To:         ataRef(to),
+		Paragraphs: alignParagraphs(numberedParagraphs(from), numberedParagraphs(to)),
+	}
+	for _, p := range resp.Paragraphs {
+		switch p.Status {
+		case diffAdded:
+			resp.Summary.ParagraphsAdded++
+		case diffRemoved:
+			resp.Summary.ParagraphsRemoved++
+		case diffUnchanged:
+			resp.Summary.ParagraphsUnchanged++
+		case diffChanged:
+			resp.Summary.ParagraphsChanged++
+			for _, s := range p.Sentences {
+				switch s.Op {
+				case diffAdded:
+					resp.Summary.SentencesAdded++
+				case diffRemoved:
+					resp.Summary.SentencesRemoved++
+				case diffChanged:
+					resp.Summary.SentencesChanged++
+				}
+			}
+		}
+	}
+	return resp
+}
+
+// previousAta retorna a ata de maior número anterior a numero.
+func previousAta(atas map[int]CopomAta, numero int) (CopomAta, bool) {
+	var prev CopomAta
+	found := false
+	for n, ata := range atas {
+		if n < numero && (!found || n > prev.NumeroReuniao) {
+			prev, found = ata, true
+		}
+	}
+	return prev, found
+}
+
+var errDiffAgainstInvalid = errors.New("Parâmetro 'against' inválido: use prev ou o número de uma reunião.")
+
+// resolveDiffPair encontra as atas a comparar: against é "prev" (padrão) ou um número.
+func resolveDiffPair(atas map[int]CopomAta, numero int, against string) (CopomAta, CopomAta, error) {
+	to, ok := atas[numero]
+	if !ok {
+		return CopomAta{}, CopomAta{}, fmt.Errorf("Ata %d não encontrada.", numero)
+	}
+	if against == "" || against == "prev" {
+		from, ok := previousAta(atas, numero)
+		if !ok {
+			return CopomAta{}, CopomAta{}, fmt.Errorf("Não há ata anterior à %d.", numero)
+		}
+		return from, to, nil
+	}
+	other, err := strconv.Atoi(against)
+	if err != nil {
+		return CopomAta{}, CopomAta{}, errDiffAgainstInvalid
+	}
+	from, ok := atas[other]
+	if !ok {
+		return CopomAta{}, CopomAta{}, fmt.Errorf("Ata %d não encontrada.", other)
+	}
+	return from, to, nil
+}
+
+// WriteUnified escreve o diff no estilo `diff -u`, com um hunk por parágrafo alterado.
+func (d AtaDiffResponse) WriteUnified(w io.Writer) {
+	fmt.Fprintf(w, "--- Ata %d (%s)\n", d.From.NumeroReuniao, d.From.DataReuniao)
+	fmt.Fprintf(w, "+++ Ata %d (%s)\n", d.To.NumeroReuniao, d.To.DataReuniao)
+	for _, p := range d.Paragraphs {
+		switch p.Status {
+		case diffAdded:
+			fmt.Fprintf(w, "@@ +§%d @@\n", p.NewNumber)
+			for _, s := range splitSentences(p.Text) {
+				fmt.Fprintf(w, "+%s\n", s)
+			}
+		case diffRemoved:
+			fmt.Fprintf(w, "@@ -§%d @@\n", p.OldNumber)
+			for _, s := range splitSentences(p.Text) {
+				fmt.Fprintf(w, "-%s\n", s)
+			}
+		case diffChanged:
+			fmt.Fprintf(w, "@@ -§%d +§%d @@\n", p.OldNumber, p.NewNumber)
+			for _, s := range p.Sentences {
+				switch s.Op {
+				case diffEqual:
+					fmt.Fprintf(w, " %s\n", s.Old)
+				case diffRemoved:
+					fmt.Fprintf(w, "-%s\n", s.Old)
+				case diffAdded:
+					fmt.Fprintf(w, "+%s\n", s.New)
+				case diffChanged:
+					fmt.Fprintf(w, "-%s\n+%s\n", s.Old, s.New)
+				}
+			}
+		}
+	}
+}
+
+// WriteHTML escreve uma página autocontida com remoções em <del> e inclusões em <ins>.
+func (d AtaDiffResponse) WriteHTML(w io.Writer) {
+	e := html.EscapeString
+	title := fmt.Sprintf("Ata %d × Ata %d", d.From.NumeroReuniao, d.To.NumeroReuniao)
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html lang=\"pt-BR\">\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", e(title))
+	fmt.Fprint(w, `<style>
+body { font-family: Georgia, serif; max-width: 860px; margin: 2em auto; line-height: 1.5; color: #222; }
+.para { border-left: 4px solid #ccc; padding: 0.3em 1em; margin: 1em 0; }
+.para.added { border-color: #2ca02c; } .para.removed { border-color: #d62728; } .para.changed { border-color: #ff7f0e; }
+.num { font-family: monospace; color: #777; font-size: 0.85em; }
+del { background: #fdd; color: #900; } ins { background: #dfd; color: #060; text-decoration: none; }
+.summary { font-family: monospace; color: #555; }
+</style>
+</head>
+<body>
+`)
+	fmt.Fprintf(w, "<h1>%s</h1>\n", e(title))
+	fmt.Fprintf(w, "<p>De <a href=\"%s\">Ata %d</a> (%s) para <a href=\"%s\">Ata %d</a> (%s)</p>\n",
+		e(d.From.URL), d.From.NumeroReuniao, e(d.From.DataReuniao), e(d.To.URL), d.To.NumeroReuniao, e(d.To.DataReuniao))
+	s := d.Summary
+	fmt.Fprintf(w, "<p class=\"summary\">Parágrafos: +%d −%d ~%d (=%d) | Frases: +%d −%d ~%d</p>\n",
+		s.ParagraphsAdded, s.ParagraphsRemoved, s.ParagraphsChanged, s.ParagraphsUnchanged,
+		s.SentencesAdded, s.SentencesRemoved, s.SentencesChanged)
+
+	for _, p := range d.Paragraphs {
+		switch p.Status {
+		case diffAdded:
+			fmt.Fprintf(w, "<div class=\"para added\"><span class=\"num\">+§%d</span> <ins>%s</ins></div>\n", p.NewNumber, e(p.Text))
+		case diffRemoved:
+			fmt.Fprintf(w, "<div class=\"para removed\"><span class=\"num\">−§%d</span> <del>%s</del></div>\n", p.OldNumber, e(p.Text))
+		case diffChanged:
+			fmt.Fprintf(w, "<div class=\"para changed\"><span class=\"num\">§%d → §%d</span>", p.OldNumber, p.NewNumber)
+			for _, sd := range p.Sentences {
+				switch sd.Op {
+				case diffEqual:
+					fmt.Fprintf(w, " %s", e(sd.Old))
+				case diffRemoved:
+					fmt.Fprintf(w, " <del>%s</del>", e(sd.Old))
+				case diffAdded:
+					fmt.Fprintf(w, " <ins>%s</ins>", e(sd.New))
+				case diffChanged:
+					fmt.Fprintf(w, " <del>%s</del> <ins>%s</ins>", e(sd.Old), e(sd.New))
+				}
+			}
+			fmt.Fprint(w, "</div>\n")
+		}
+	}
+	fmt.Fprint(w, "</body>\n</html>\n")
+}
@@ -15,67 +15,111 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/atas": {
+        "/admin/jobs": {
             "get": {
-                "description": "Retorna metadados de todas as atas (sem o conteúdo completo)",
+                "description": "Jobs do mais recente para o mais antigo, sem os logs (os terminados mais antigos são descartados).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Atas"
+                    "Admin"
                 ],
-                "summary": "Lista todas as atas do COPOM",
+                "summary": "Lista os jobs",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/main.CopomAta"
+                                "$ref": "#/definitions/main.Job"
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/atas/numeros": {
-            "get": {
-                "description": "Retorna array com os números de todas as reuniões (ordenado decrescente)",
+        "/admin/jobs/enrich": {
+            "post": {
+                "description": "Executa o scraping (scrape) ou o enriquecimento via Gemini (enrich) em segundo plano e retorna o job criado.\nSó um job roda por vez. Ao terminar, os datasets são recarregados nos dados servidos.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Atas"
+                    "Admin"
                 ],
-                "summary": "Lista números das reuniões disponíveis",
+                "summary": "Dispara um job de scrape ou enrich",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/main.Job"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/atas/{numero}": {
+        "/admin/jobs/scrape": {
+            "post": {
+                "description": "Executa o scraping (scrape) ou o enriquecimento via Gemini (enrich) em segundo plano e retorna o job criado.\nSó um job roda por vez. Ao terminar, os datasets são recarregados nos dados servidos.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Dispara um job de scrape ou enrich",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/main.Job"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/jobs/{id}": {
             "get": {
-                "description": "Retorna uma ata específica com conteúdo completo",
+                "description": "Retorna status, progresso, erro e as últimas linhas de log do job.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Atas"
+                    "Admin"
                 ],
-                "summary": "Busca ata por número da reunião",
+                "summary": "Status de um job",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Número da reunião",
-                        "name": "numero",
+                        "type": "string",
+                        "description": "ID do job",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -84,39 +128,224 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.CopomAta"
+                            "$ref": "#/definitions/main.Job"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorResponse"
                         }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/jobs/{id}/cancel": {
+            "post": {
+                "description": "Pede o cancelamento do job; ele para no próximo ponto de verificação (entre atas no scrape, entre parágrafos no enrich)\ne o trabalho já feito fica salvo. Acompanhe o status em GET /admin/jobs/{id}.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Cancela um job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do job",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/main.Job"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/enriched": {
+        "/admin/reload": {
+            "post": {
+                "description": "Relê dataset_raw.json e dataset_enriched.json e troca os dados servidos sem reiniciar o servidor.\nSe um dos arquivos não puder ser lido, os dados atuais dele são mantidos e o erro é retornado.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Recarrega os datasets do disco",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.ReloadResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/schedule": {
             "get": {
-                "description": "Retorna parágrafos com análise de sentimento do Gemini AI",
+                "description": "Próxima reunião aguardada, publicação esperada da ata e próxima tentativa de scrape. Disponível apenas no modo 'daemon'.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enriched"
+                    "Admin"
                 ],
-                "summary": "Lista parágrafos enriquecidos (paginado)",
+                "summary": "Estado do agendador",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.ScheduleStatus"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/webhooks": {
+            "get": {
+                "description": "Webhooks lidos de webhooks.json (ou WEBHOOKS_CONFIG) e os eventos que cada um recebe. Os secrets não são expostos.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Lista os webhooks configurados",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/main.WebhookInfo"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/webhooks/deliveries": {
+            "get": {
+                "description": "Entregas da mais recente para a mais antiga (apenas as últimas 200, em memória), com tentativas e o resultado da última.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Log de entregas dos webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do webhook",
+                        "name": "webhook",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "succeeded",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Status da entrega",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/main.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/atas": {
+            "get": {
+                "description": "Retorna metadados das atas (sem o conteúdo completo), por padrão da mais recente para a mais antiga.\nCom ?format=csv|jsonl|parquet (ou header Accept correspondente) retorna todas as atas filtradas (sem paginação) como arquivo tabular.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.apache.parquet"
+                ],
+                "tags": [
+                    "Atas"
+                ],
+                "summary": "Lista as atas do COPOM (paginado)",
                 "parameters": [
                     {
                         "type": "integer",
                         "default": 1,
-                        "description": "Número da página",
+                        "description": "Número da página (ignorado quando cursor é informado)",
                         "name": "page",
                         "in": "query"
                     },
@@ -127,10 +356,66 @@ const docTemplate = `{
                         "name": "limit",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco retornado em next_cursor/prev_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data mínima da reunião (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data máxima da reunião (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Filtrar por número da reunião",
-                        "name": "meeting",
+                        "description": "Ano da reunião",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apenas atas com (true) ou sem (false) conteúdo",
+                        "name": "has_content",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apenas atas com (true) ou sem (false) falha no parse",
+                        "name": "falha_no_parse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-numero_reuniao",
+                        "description": "Ordenação: campos separados por vírgula, '-' para decrescente",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "jsonl",
+                            "parquet"
+                        ],
+                        "type": "string",
+                        "description": "Formato da resposta",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Incluir o conteúdo completo nos formatos tabulares",
+                        "name": "include_content",
                         "in": "query"
                     }
                 ],
@@ -138,7 +423,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.PaginatedResponse"
+                            "$ref": "#/definitions/main.PaginatedResponse-main_CopomAta"
                         }
                     },
                     "400": {
@@ -147,26 +432,54 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/enriched/meeting/{numero}": {
+        "/atas/numeros": {
             "get": {
-                "description": "Retorna todos os parágrafos enriquecidos de uma reunião do COPOM",
+                "description": "Retorna array com os números das reuniões (ordenado decrescente), aceitando os mesmos filtros de /atas",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enriched"
+                    "Atas"
                 ],
-                "summary": "Lista parágrafos de uma reunião específica",
+                "summary": "Lista números das reuniões disponíveis",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Data mínima da reunião (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data máxima da reunião (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Número da reunião",
-                        "name": "numero",
-                        "in": "path",
-                        "required": true
+                        "description": "Ano da reunião",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apenas atas com (true) ou sem (false) conteúdo",
+                        "name": "has_content",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apenas atas com (true) ou sem (false) falha no parse",
+                        "name": "falha_no_parse",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -175,7 +488,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/main.EnrichedParagraph"
+                                "type": "integer"
                             }
                         }
                     },
@@ -184,32 +497,31 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/main.ErrorResponse"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/main.ErrorResponse"
-                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/enriched/{id}": {
+        "/atas/{numero}": {
             "get": {
-                "description": "Retorna um parágrafo enriquecido específico",
+                "description": "Retorna uma ata específica com conteúdo completo",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enriched"
+                    "Atas"
                 ],
-                "summary": "Busca parágrafo por ID global",
+                "summary": "Busca ata por número da reunião",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Global ID do parágrafo",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Número da reunião",
+                        "name": "numero",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -217,7 +529,232 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.EnrichedParagraph"
+                            "$ref": "#/definitions/main.CopomAta"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/atas/{numero}/diff": {
+            "get": {
+                "description": "Alinha os parágrafos numerados das duas atas por similaridade e compara as frases dos parágrafos alterados.\nPor padrão compara com a ata anterior (against=prev).",
+                "produces": [
+                    "application/json",
+                    "text/x-diff",
+                    "text/html"
+                ],
+                "tags": [
+                    "Atas"
+                ],
+                "summary": "Diff entre duas atas",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Número da reunião",
+                        "name": "numero",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "prev",
+                        "description": "prev ou o número da reunião a comparar",
+                        "name": "against",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "unified",
+                            "html"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Formato da resposta",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.AtaDiffResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/atas/{numero}/facts": {
+            "get": {
+                "description": "Números extraídos do texto da ata (expectativas de inflação do Focus, projeções do Copom, horizonte relevante,\ndecisão e trajetória da Selic, PIB, câmbio de referência), cada um com o parágrafo e a frase de origem.\nPeríodos são anos (\"2025\"), trimestres (\"2027Q2\") ou a data da reunião, para fatos pontuais.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Atas"
+                ],
+                "summary": "Fatos numéricos de uma ata",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Número da reunião",
+                        "name": "numero",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "inflation_expectation",
+                            "inflation_projection",
+                            "relevant_horizon_projection",
+                            "free_prices_projection",
+                            "administered_prices_projection",
+                            "selic_decision",
+                            "selic_path",
+                            "gdp_growth",
+                            "exchange_rate_assumption"
+                        ],
+                        "type": "string",
+                        "description": "Tipos de fato separados por vírgula",
+                        "name": "kind",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.AtaFactsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/charts": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gráficos"
+                ],
+                "summary": "Lista os gráficos disponíveis",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/main.ChartInfo"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/charts/{name}": {
+            "get": {
+                "description": "Gera sob demanda os gráficos do antigo graficos_dataset.py (ex: /charts/evolucao-dolar.svg).\nApenas SVG é suportado; pedidos .png retornam 415.",
+                "produces": [
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "Gráficos"
+                ],
+                "summary": "Renderiza um gráfico do dataset em SVG",
+                "parameters": [
+                    {
+                        "enum": [
+                            "evolucao-dolar.svg",
+                            "evolucao-ipca.svg",
+                            "atas-por-ano.svg",
+                            "correlacao-dolar-ipca.svg",
+                            "tamanho-atas.svg",
+                            "boxplot-ipca-decada.svg",
+                            "dolar-ipca-dual.svg",
+                            "completude.svg"
+                        ],
+                        "type": "string",
+                        "description": "Nome do gráfico com extensão .svg",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data mínima da reunião (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data máxima da reunião (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Documento SVG",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -231,55 +768,1410 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/main.ErrorResponse"
                         }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/enriched": {
+            "get": {
+                "description": "Retorna parágrafos com análise de sentimento do Gemini AI, por padrão ordenados por global_id.\nAceita paginação por page/limit ou por cursor (next/prev na resposta), estável entre recargas do dataset.\nCom ?format=csv|jsonl|parquet (ou header Accept correspondente) retorna todos os parágrafos filtrados (sem paginação) como arquivo tabular.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.apache.parquet"
+                ],
+                "tags": [
+                    "Enriched"
+                ],
+                "summary": "Lista parágrafos enriquecidos (paginado)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Número da página (ignorado quando cursor é informado)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Itens por página (máx 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco retornado em next_cursor/prev_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filtrar por número da reunião",
+                        "name": "meeting",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número mínimo da reunião",
+                        "name": "meeting_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número máximo da reunião",
+                        "name": "meeting_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data mínima da reunião (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data máxima da reunião (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "SUBIR",
+                            "DESCER",
+                            "NEUTRO"
+                        ],
+                        "type": "string",
+                        "description": "Tendência do dólar (aceita lista separada por vírgula)",
+                        "name": "dollar_trend",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "SUBIR",
+                            "DESCER",
+                            "NEUTRO"
+                        ],
+                        "type": "string",
+                        "description": "Tendência do IPCA (aceita lista separada por vírgula)",
+                        "name": "ipca_trend",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Valor mínimo do dólar",
+                        "name": "dollar_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Valor máximo do dólar",
+                        "name": "dollar_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Valor mínimo do IPCA",
+                        "name": "ipca_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Valor máximo do IPCA",
+                        "name": "ipca_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Texto contido no parágrafo (ignora acentos e maiúsculas)",
+                        "name": "contains",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "external_scenario",
+                            "domestic_activity",
+                            "labor_market",
+                            "inflation_expectations",
+                            "fiscal_policy",
+                            "exchange_rate",
+                            "balance_of_risks",
+                            "forward_guidance"
+                        ],
+                        "type": "string",
+                        "description": "Tópico do parágrafo (aceita lista separada por vírgula; basta um coincidir)",
+                        "name": "topic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ordenação: campos separados por vírgula, '-' para decrescente (ex: -meeting_date,paragraph_id). Campos: global_id, paragraph_id, meeting_number, meeting_date, url, paragraph, dollar_value, ipca_value, dollar_trend, ipca_trend, reasoning",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "jsonl",
+                            "parquet"
+                        ],
+                        "type": "string",
+                        "description": "Formato da resposta",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Incluir o texto do parágrafo nos formatos tabulares",
+                        "name": "include_content",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Incluir o vetor de embedding de cada parágrafo",
+                        "name": "include_embedding",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.PaginatedResponse-main_EnrichedParagraph"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/enriched/meeting/{numero}": {
+            "get": {
+                "description": "Retorna todos os parágrafos enriquecidos de uma reunião do COPOM",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enriched"
+                ],
+                "summary": "Lista parágrafos de uma reunião específica",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Número da reunião",
+                        "name": "numero",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Incluir o vetor de embedding de cada parágrafo",
+                        "name": "include_embedding",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/main.EnrichedParagraph"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/enriched/{id}": {
+            "get": {
+                "description": "Retorna um parágrafo enriquecido específico",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enriched"
+                ],
+                "summary": "Busca parágrafo por ID global",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Global ID do parágrafo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Incluir o vetor de embedding",
+                        "name": "include_embedding",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.EnrichedParagraph"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/enriched/{id}/similar": {
+            "get": {
+                "description": "Retorna os k parágrafos mais próximos (similaridade de cosseno dos embeddings), por padrão de outras reuniões,\npara acompanhar como um tema evoluiu ao longo das atas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enriched"
+                ],
+                "summary": "Parágrafos semanticamente parecidos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Global ID do parágrafo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Quantidade de resultados (máx 50)",
+                        "name": "k",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Incluir parágrafos da mesma reunião",
+                        "name": "same_meeting",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.SimilarResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Stream Server-Sent Events com os eventos do servidor: ata.scraped, enrichment.completed, paragraph.enriched,\ndataset.reloaded, job.started, job.progress e job.finished. Cada evento tem id sequencial e o JSON do Event em data.\nAo reconectar com o cabeçalho Last-Event-ID (ou ?last_event_id=) os eventos perdidos são reenviados a partir do buffer\nem memória; se parte deles já foi descartada, um evento stream.gap (sem id) avisa o cliente.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Eventos"
+                ],
+                "summary": "Eventos do pipeline (SSE)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tipos de evento separados por vírgula (padrão: todos)",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Alternativa ao cabeçalho Last-Event-ID",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Último evento recebido",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/feed.atom": {
+            "get": {
+                "description": "Uma entrada por reunião, da mais recente para a mais antiga: título, data, link para a ata no site do BCB,\nresumo da decisão sobre a Selic (comparada com a reunião anterior) e os primeiros parágrafos da ata.",
+                "produces": [
+                    "application/atom+xml"
+                ],
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Feed Atom das atas",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Número de entradas (máx 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Feed Atom",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/feed.rss": {
+            "get": {
+                "description": "Mesmo conteúdo de /feed.atom em RSS 2.0; a descrição de cada item traz a decisão e os primeiros parágrafos em HTML.",
+                "produces": [
+                    "application/rss+xml"
+                ],
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Feed RSS das atas",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Número de entradas (máx 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Feed RSS",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/guidance": {
+            "get": {
+                "description": "Detecta nas atas as frases de sinalização do Copom (ex: \"interrupção do ciclo\", \"período bastante prolongado\",\n\"não hesitará em retomar\"), normalizadas para identificadores estáveis, e informa quando cada uma apareceu e desapareceu.\nReuniões sem conteúdo são ignoradas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Séries"
+                ],
+                "summary": "Linha do tempo do forward guidance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Data mínima da reunião (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data máxima da reunião (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.GuidanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Busca com normalização de acentos e stemming em português (ex: \"desancoragem\" encontra \"desancoradas\").\nResultados ordenados por relevância (BM25), com trecho destacado com \u003cmark\u003e.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Busca"
+                ],
+                "summary": "Busca textual nas atas e parágrafos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Termos da busca",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "ata",
+                            "paragraph"
+                        ],
+                        "type": "string",
+                        "description": "Restringir a atas ou parágrafos",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Máximo de resultados (máx 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/stats": {
+            "get": {
+                "description": "Contagem de atas por ano, estatísticas de dólar/IPCA/tamanho do conteúdo, completude dos campos,\ntaxa de falhas no parse e distribuição dos rótulos de tendência dos parágrafos enriquecidos.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Séries"
+                ],
+                "summary": "Estatísticas dos datasets",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.StatsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/timeseries": {
+            "get": {
+                "description": "Retorna arrays alinhados por data da reunião (ordem cronológica) com dólar, IPCA e sentimento agregado.\nO sentimento de cada reunião é (SUBIR − DESCER) / n sobre os parágrafos enriquecidos, de -1 a 1. Valores ausentes vêm como null.\nCom ?topic= o sentimento é calculado por tópico (ex: só forward_guidance).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Séries"
+                ],
+                "summary": "Séries temporais por reunião",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "dollar,ipca,dollar_sentiment,ipca_sentiment",
+                        "description": "Séries separadas por vírgula (padrão: todas)",
+                        "name": "series",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data mínima da reunião (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data máxima da reunião (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "external_scenario",
+                            "domestic_activity",
+                            "labor_market",
+                            "inflation_expectations",
+                            "fiscal_policy",
+                            "exchange_rate",
+                            "balance_of_risks",
+                            "forward_guidance"
+                        ],
+                        "type": "string",
+                        "description": "Considera no sentimento apenas parágrafos destes tópicos (lista separada por vírgula)",
+                        "name": "topic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.TimeSeriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "main.AtaDiffResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "$ref": "#/definitions/main.AtaRef"
+                },
+                "paragraphs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.ParagraphDiff"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/main.DiffSummary"
+                },
+                "to": {
+                    "$ref": "#/definitions/main.AtaRef"
+                }
+            }
+        },
+        "main.AtaFact": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "type": "string"
+                },
+                "paragraph": {
+                    "description": "Paragraph é o número do parágrafo da ata (0 para a Tabela 1) e Text a frase de origem.",
+                    "type": "integer"
+                },
+                "period": {
+                    "description": "\"2025\", \"2027Q2\" ou a data da reunião (YYYY-MM-DD) para decisões",
+                    "type": "string"
+                },
+                "scenario": {
+                    "description": "reference | alternative (apenas projeções)",
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "\"%\", \"% a.a.\" ou \"R$/US$\"",
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "main.AtaFactsResponse": {
+            "type": "object",
+            "properties": {
+                "data_reuniao": {
+                    "type": "string"
+                },
+                "facts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.AtaFact"
+                    }
+                },
+                "numero_reuniao": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.AtaRef": {
+            "type": "object",
+            "properties": {
+                "data_reuniao": {
+                    "type": "string"
+                },
+                "numero_reuniao": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.AtasStats": {
+            "type": "object",
+            "properties": {
+                "completeness": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.FieldCompleteness"
+                    }
+                },
+                "content_length": {
+                    "$ref": "#/definitions/main.NumericStats"
+                },
+                "dollar": {
+                    "$ref": "#/definitions/main.NumericStats"
+                },
+                "dollar_ipca_correlation": {
+                    "type": "number"
+                },
+                "duplicate_meetings": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "first_date": {
+                    "type": "string"
+                },
+                "gaps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.DateGap"
+                    }
+                },
+                "ipca": {
+                    "$ref": "#/definitions/main.NumericStats"
+                },
+                "last_date": {
+                    "type": "string"
+                },
+                "parse_failure_rate": {
+                    "type": "number"
+                },
+                "parse_failures": {
+                    "type": "integer"
+                },
+                "per_year": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "quality": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.ChartInfo": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.CopomAta": {
+            "type": "object",
+            "properties": {
+                "conteudo": {
+                    "type": "string"
+                },
+                "data_reuniao": {
+                    "description": "Formato YYYY-MM-DD",
+                    "type": "string"
+                },
+                "falha_no_parse": {
+                    "type": "boolean"
+                },
+                "numero_reuniao": {
+                    "type": "integer"
+                },
+                "titulo": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "valor_dolar": {
+                    "description": "Dólar PTAX na data",
+                    "type": "number"
+                },
+                "valor_ipca": {
+                    "description": "IPCA do mês da reunião",
+                    "type": "number"
+                }
+            }
+        },
+        "main.DateGap": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.DiffSummary": {
+            "type": "object",
+            "properties": {
+                "paragraphs_added": {
+                    "type": "integer"
+                },
+                "paragraphs_changed": {
+                    "type": "integer"
+                },
+                "paragraphs_removed": {
+                    "type": "integer"
+                },
+                "paragraphs_unchanged": {
+                    "type": "integer"
+                },
+                "sentences_added": {
+                    "type": "integer"
+                },
+                "sentences_changed": {
+                    "type": "integer"
+                },
+                "sentences_removed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.EnrichedParagraph": {
+            "type": "object",
+            "properties": {
+                "dollar_value": {
+                    "type": "number"
+                },
+                "embedding": {
+                    "description": "Embedding só é preenchido nas respostas que o pedem (include_embedding); o vetor fica\ngravado em dataset_embeddings.json, não no dataset enriquecido.",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "global_id": {
+                    "type": "integer"
+                },
+                "ipca_value": {
+                    "type": "number"
+                },
+                "meeting_date": {
+                    "type": "string"
+                },
+                "meeting_number": {
+                    "type": "integer"
+                },
+                "paragraph": {
+                    "type": "string"
+                },
+                "paragraph_id": {
+                    "description": "Sequencial dentro da reunião",
+                    "type": "integer"
+                },
+                "prediction": {
+                    "$ref": "#/definitions/main.GeminiPrediction"
+                },
+                "topics": {
+                    "description": "Ver allTopics",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.EnrichedStats": {
+            "type": "object",
+            "properties": {
+                "dollar_trends": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "ipca_trends": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "meetings": {
+                    "type": "integer"
+                },
+                "paragraphs_per_meeting": {
+                    "$ref": "#/definitions/main.NumericStats"
+                },
+                "topics": {
+                    "description": "parágrafos por tópico (um parágrafo conta em cada tópico que tiver)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "without_topics": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.Event": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "data": {},
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.FieldCompleteness": {
+            "type": "object",
+            "properties": {
+                "empty": {
+                    "type": "integer"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "filled": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "main.GeminiPrediction": {
+            "type": "object",
+            "properties": {
+                "dollar_trend": {
+                    "description": "\"SUBIR\", \"DESCER\", \"NEUTRO\"",
+                    "type": "string"
+                },
+                "ipca_trend": {
+                    "description": "\"SUBIR\", \"DESCER\", \"NEUTRO\"",
+                    "type": "string"
+                },
+                "reasoning": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.GuidanceMeeting": {
+            "type": "object",
+            "properties": {
+                "data_reuniao": {
+                    "type": "string"
+                },
+                "numero_reuniao": {
+                    "type": "integer"
+                },
+                "occurrences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.GuidanceOccurrence"
+                    }
+                },
+                "phrases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "main.GuidanceOccurrence": {
+            "type": "object",
+            "properties": {
+                "paragraph": {
+                    "type": "integer"
+                },
+                "phrase": {
+                    "type": "string"
+                },
+                "sentence": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.GuidancePhraseTimeline": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "disappeared_date": {
+                    "type": "string"
+                },
+                "disappeared_meeting": {
+                    "type": "integer"
+                },
+                "first_seen_date": {
+                    "type": "string"
+                },
+                "first_seen_meeting": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "last_seen_date": {
+                    "type": "string"
+                },
+                "last_seen_meeting": {
+                    "type": "integer"
+                },
+                "latest_sentence": {
+                    "type": "string"
+                },
+                "meetings": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "spans": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.GuidanceSpan"
+                    }
+                }
+            }
+        },
+        "main.GuidanceResponse": {
+            "type": "object",
+            "properties": {
+                "meetings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.GuidanceMeeting"
+                    }
+                },
+                "phrases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.GuidancePhraseTimeline"
+                    }
+                }
+            }
+        },
+        "main.GuidanceSpan": {
+            "type": "object",
+            "properties": {
+                "from_date": {
+                    "type": "string"
+                },
+                "from_meeting": {
+                    "type": "integer"
+                },
+                "to_date": {
+                    "type": "string"
+                },
+                "to_meeting": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.Job": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "logs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
+                },
+                "progress": {
+                    "$ref": "#/definitions/main.JobProgress"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "main.CopomAta": {
+        },
+        "main.JobProgress": {
             "type": "object",
             "properties": {
-                "conteudo": {
+                "done": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.NumericStats": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "number"
+                },
+                "mean": {
+                    "type": "number"
+                },
+                "median": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                },
+                "outliers": {
+                    "description": "Outliers pelo critério de 1,5 × IQR",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "stddev": {
+                    "type": "number"
+                }
+            }
+        },
+        "main.PaginatedResponse-main_CopomAta": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.CopomAta"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next": {
+                    "description": "Links para a página seguinte/anterior via cursor (?cursor=); vazios nas pontas.",
                     "type": "string"
                 },
-                "data_reuniao": {
-                    "description": "Formato YYYY-MM-DD",
+                "next_cursor": {
                     "type": "string"
                 },
-                "falha_no_parse": {
-                    "type": "boolean"
+                "page": {
+                    "type": "integer"
                 },
-                "numero_reuniao": {
+                "prev": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
+                "total": {
                     "type": "integer"
                 },
-                "titulo": {
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "main.PaginatedResponse-main_EnrichedParagraph": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.EnrichedParagraph"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next": {
+                    "description": "Links para a página seguinte/anterior via cursor (?cursor=); vazios nas pontas.",
                     "type": "string"
                 },
-                "url": {
+                "next_cursor": {
                     "type": "string"
                 },
-                "valor_dolar": {
-                    "description": "Dólar PTAX na data",
-                    "type": "number"
+                "page": {
+                    "type": "integer"
                 },
-                "valor_ipca": {
-                    "description": "IPCA do mês da reunião",
-                    "type": "number"
+                "prev": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "main.EnrichedParagraph": {
+        "main.ParagraphDiff": {
             "type": "object",
             "properties": {
-                "dollar_value": {
+                "new_number": {
+                    "type": "integer"
+                },
+                "old_number": {
+                    "type": "integer"
+                },
+                "sentences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.SentenceDiff"
+                    }
+                },
+                "similarity": {
                     "type": "number"
                 },
+                "status": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.ReloadResult": {
+            "type": "object",
+            "properties": {
+                "atas": {
+                    "type": "integer"
+                },
+                "paragraphs": {
+                    "type": "integer"
+                },
+                "reloaded_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.ScheduleStatus": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "calendar": {
+                    "type": "string"
+                },
+                "expected_publication": {
+                    "type": "string"
+                },
+                "last_job": {
+                    "type": "string"
+                },
+                "last_message": {
+                    "type": "string"
+                },
+                "next_attempt": {
+                    "type": "string"
+                },
+                "next_meeting": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.SearchHit": {
+            "type": "object",
+            "properties": {
                 "global_id": {
                     "type": "integer"
                 },
-                "ipca_value": {
-                    "type": "number"
+                "kind": {
+                    "description": "\"ata\" ou \"paragraph\"",
+                    "type": "string"
                 },
                 "meeting_date": {
                     "type": "string"
@@ -287,65 +2179,186 @@ const docTemplate = `{
                 "meeting_number": {
                     "type": "integer"
                 },
-                "paragraph": {
-                    "type": "string"
-                },
                 "paragraph_id": {
-                    "description": "Sequencial dentro da reunião",
                     "type": "integer"
                 },
-                "prediction": {
-                    "$ref": "#/definitions/main.GeminiPrediction"
+                "score": {
+                    "type": "number"
+                },
+                "snippet": {
+                    "description": "Trecho com os termos encontrados entre \u003cmark\u003e\u003c/mark\u003e",
+                    "type": "string"
                 }
             }
         },
-        "main.ErrorResponse": {
+        "main.SearchResponse": {
             "type": "object",
             "properties": {
-                "error": {
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.SearchHit"
+                    }
+                },
+                "query": {
                     "type": "string"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "main.GeminiPrediction": {
+        "main.SentenceDiff": {
             "type": "object",
             "properties": {
-                "dollar_trend": {
-                    "description": "\"SUBIR\", \"DESCER\", \"NEUTRO\"",
+                "new": {
                     "type": "string"
                 },
-                "ipca_trend": {
-                    "description": "\"SUBIR\", \"DESCER\", \"NEUTRO\"",
+                "old": {
                     "type": "string"
                 },
-                "reasoning": {
+                "op": {
                     "type": "string"
                 }
             }
         },
-        "main.PaginatedResponse": {
+        "main.SimilarParagraph": {
             "type": "object",
             "properties": {
-                "data": {
+                "paragraph": {
+                    "$ref": "#/definitions/main.EnrichedParagraph"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "main.SimilarResponse": {
+            "type": "object",
+            "properties": {
+                "provider": {
+                    "type": "string"
+                },
+                "results": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/main.EnrichedParagraph"
+                        "$ref": "#/definitions/main.SimilarParagraph"
                     }
                 },
-                "limit": {
+                "source": {
+                    "$ref": "#/definitions/main.EnrichedParagraph"
+                }
+            }
+        },
+        "main.StatsResponse": {
+            "type": "object",
+            "properties": {
+                "atas": {
+                    "$ref": "#/definitions/main.AtasStats"
+                },
+                "enriched": {
+                    "$ref": "#/definitions/main.EnrichedStats"
+                },
+                "generated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.TimeSeriesResponse": {
+            "type": "object",
+            "properties": {
+                "dates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "meetings": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "series": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "number",
+                            "format": "float64"
+                        }
+                    }
+                }
+            }
+        },
+        "main.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
                     "type": "integer"
                 },
-                "page": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Da última tentativa",
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "event_id": {
                     "type": "integer"
                 },
-                "total": {
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "numero_reuniao": {
                     "type": "integer"
                 },
-                "total_pages": {
+                "status": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "description": "Da última tentativa",
                     "type": "integer"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "main.WebhookInfo": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "signed": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "Exigida apenas quando api_keys.json existe (escopo read para consultas, admin para /admin).",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        }
     }
 }`
 
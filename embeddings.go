@@ -0,0 +1,355 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Embeddings dos parágrafos enriquecidos, usados em GET /enriched/:id/similar.
+//
+// Os vetores ficam em dataset_embeddings.json, ao lado do dataset enriquecido, indexados
+// pelo hash do texto do parágrafo (paragraphTextHash). Assim sobrevivem a reatribuições
+// de GlobalID (import) e não incham o dataset_enriched.json servido pela API. Cada
+// EnrichedParagraph recebe o seu vetor em Embedding quando a resposta ou a exportação o
+// pede (include_embedding, -embeddings).
+
+const embeddingsFilename = "dataset_embeddings.json"
+
+// embeddingBatchSize limita quantos textos vão em cada chamada ao provedor.
+const embeddingBatchSize = 100
+
+// embeddingProvider gera vetores para textos. Vetores de provedores diferentes não são
+// comparáveis, por isso Name identifica também o modelo/dimensão.
+type embeddingProvider interface {
+	Name() string
+	Embed(texts []string) ([][]float32, error)
+}
+
+// newEmbeddingProvider escolhe o provedor por EMBEDDING_PROVIDER: "hashing" (padrão,
+// offline) ou "gemini" (requer GEMINI_API_KEY).
+func newEmbeddingProvider() (embeddingProvider, error) {
+	switch name := strings.ToLower(os.Getenv("EMBEDDING_PROVIDER")); name {
+	case "", "hashing":
+		return hashingEmbedder{dims: hashingEmbeddingDims}, nil
+	case "gemini":
+		apiKey := os.Getenv("GEMINI_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("GEMINI_API_KEY não definida (necessária para EMBEDDING_PROVIDER=gemini)")
+		}
+		return geminiEmbedder{apiKey: apiKey}, nil
+	default:
+		return nil, fmt.Errorf("EMBEDDING_PROVIDER desconhecido: %s. Use hashing ou gemini", name)
+	}
+}
+
+// embeddingProviderFor é o provedor usado para atualizar set: sem EMBEDDING_PROVIDER, segue o
+// provedor já gravado no arquivo, para não descartar vetores pagos de um -mode=embed anterior.
+func embeddingProviderFor(set embeddingSet) (embeddingProvider, error) {
+	if os.Getenv("EMBEDDING_PROVIDER") == "" && set.Provider != "" {
+		gemini := geminiEmbedder{apiKey: os.Getenv("GEMINI_API_KEY")}
+		if set.Provider == gemini.Name() {
+			if gemini.apiKey == "" {
+				return nil, fmt.Errorf("GEMINI_API_KEY não definida (necessária para os embeddings %s do arquivo)", set.Provider)
+			}
+			return gemini, nil
+		}
+	}
+	return newEmbeddingProvider()
+}
+
+// --- Provedor offline: hashing de termos ---
+
+const hashingEmbeddingDims = 512
+
+// hashingEmbedder projeta os termos (com acentos removidos, sem stopwords e com stemming,
+// como na busca) e os bigramas de termos em um vetor de dimensão fixa, usando o sinal do
+// hash para reduzir o viés das colisões. O peso de cada termo é 1 + ln(tf).
+type hashingEmbedder struct {
+	dims int
+}
+
+func (h hashingEmbedder) Name() string {
+	return fmt.Sprintf("hashing-%d", h.dims)
+}
+
+func (h hashingEmbedder) Embed(texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = h.embed(text)
+	}
+	return vectors, nil
+}
+
+func (h hashingEmbedder) embed(text string) []float32 {
+	counts := make(map[string]float64)
+	tokens := tokenizeForSearch(text)
+	for i, tok := range tokens {
+		counts[tok.Term]++
+		if i > 0 {
+			// Bigramas pesam metade: capturam expressões como "politica monetaria"
+			counts[tokens[i-1].Term+" "+tok.Term] += 0.5
+		}
+	}
+
+	vec := make([]float32, h.dims)
+	for feature, tf := range counts {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(feature))
+		sum := hasher.Sum64()
+		weight := 1 + math.Log(tf)
+		if tf < 1 {
+			weight = tf
+		}
+		if sum>>63 == 1 {
+			weight = -weight
+		}
+		vec[sum%uint64(h.dims)] += float32(weight)
+	}
+	return normalizeVector(vec)
+}
+
+// normalizeVector aplica norma L2 unitária, para que o cosseno seja o produto escalar.
+func normalizeVector(vec []float32) []float32 {
+	var norm float64
+	for _, v := range vec {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		return vec
+	}
+	inv := float32(1 / math.Sqrt(norm))
+	for i := range vec {
+		vec[i] *= inv
+	}
+	return vec
+}
+
+func dotProduct(a, b []float32) float64 {
+	var sum float64
+	for i := range min(len(a), len(b)) {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// --- Persistência ---
+
+// embeddingSet é o conteúdo de dataset_embeddings.json.
+type embeddingSet struct {
+	Provider string               `json:"provider"`
+	Dims     int                  `json:"dims"`
+	Vectors  map[string][]float32 `json:"vectors"` // chave: paragraphTextHash
+}
+
+// loadEmbeddings lê o arquivo de embeddings; se não existir, retorna um conjunto vazio.
+func loadEmbeddings(filename string) (embeddingSet, error) {
+	var set embeddingSet
+	if _, err := loadJSONDataset(filename, &set); err != nil && !errors.Is(err, ErrDatasetNotFound) {
+		return embeddingSet{}, err
+	}
+	if set.Vectors == nil {
+		set.Vectors = make(map[string][]float32)
+	}
+	return set, nil
+}
+
+// updateEmbeddings calcula os vetores que faltam para os parágrafos e descarta os de textos
+// que não existem mais. Se o provedor mudou, todos os vetores são recalculados.
+// Retorna quantos vetores foram calculados.
+func updateEmbeddings(set *embeddingSet, provider embeddingProvider, paragraphs []EnrichedParagraph) (int, error) {
+	if set.Provider != provider.Name() {
+		if len(set.Vectors) > 0 {
+			log.Printf("Provedor de embeddings mudou (%s -> %s); recalculando todos os vetores.", set.Provider, provider.Name())
+		}
+		set.Provider = provider.Name()
+		set.Vectors = make(map[string][]float32)
+	}
+
+	live := make(map[string]bool, len(paragraphs))
+	var pendingHashes []string
+	var pendingTexts []string
+	for _, p := range paragraphs {
+		hash := paragraphTextHash(p.Paragraph)
+		if live[hash] {
+			continue
+		}
+		live[hash] = true
+		if _, ok := set.Vectors[hash]; !ok {
+			pendingHashes = append(pendingHashes, hash)
+			pendingTexts = append(pendingTexts, p.Paragraph)
+		}
+	}
+	for hash := range set.Vectors {
+		if !live[hash] {
+			delete(set.Vectors, hash)
+		}
+	}
+
+	computed := 0
+	for start := 0; start < len(pendingTexts); start += embeddingBatchSize {
+		end := min(start+embeddingBatchSize, len(pendingTexts))
+		vectors, err := provider.Embed(pendingTexts[start:end])
+		if err != nil {
+			return computed, err
+		}
+		if len(vectors) != end-start {
+			return computed, fmt.Errorf("provedor %s retornou %d vetores para %d textos", provider.Name(), len(vectors), end-start)
+		}
+		for i, vec := range vectors {
+			set.Vectors[pendingHashes[start+i]] = normalizeVector(vec)
+			set.Dims = len(vec)
+		}
+		computed += len(vectors)
+		if end < len(pendingTexts) {
+			log.Printf("  Embeddings: %d/%d", end, len(pendingTexts))
+		}
+	}
+	return computed, nil
+}
+
+// withEmbeddings retorna cópias dos parágrafos com Embedding preenchido pelos vetores do
+// arquivo (nil para os que ainda não têm embedding).
+func (set embeddingSet) withEmbeddings(paragraphs []EnrichedParagraph) []EnrichedParagraph {
+	out := make([]EnrichedParagraph, len(paragraphs))
+	for i, p := range paragraphs {
+		p.Embedding = set.Vectors[paragraphTextHash(p.Paragraph)]
+		out[i] = p
+	}
+	return out
+}
+
+// refreshEmbeddingsFile atualiza dataset_embeddings.json para os parágrafos informados.
+func refreshEmbeddingsFile(filename string, paragraphs []EnrichedParagraph) error {
+	set, err := loadEmbeddings(filename)
+	if err != nil {
+		return err
+	}
+	provider, err := embeddingProviderFor(set)
+	if err != nil {
+		return err
+	}
+	computed, err := updateEmbeddings(&set, provider, paragraphs)
+	if computed > 0 || err == nil {
+		// Mesmo em caso de erro, grava o que já foi calculado (chamadas pagas não se perdem)
+		if saveErr := saveJSONDataset(filename, set); saveErr != nil {
+			return errors.Join(err, saveErr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("Embeddings (%s): %d calculados, %d no total.", set.Provider, computed, len(set.Vectors))
+	return nil
+}
+
+// --- Índice de similaridade ---
+
+// SimilarParagraph é um resultado de GET /enriched/:id/similar.
+type SimilarParagraph struct {
+	Score     float64           `json:"score"`
+	Paragraph EnrichedParagraph `json:"paragraph"`
+}
+
+// SimilarResponse é o corpo de GET /enriched/:id/similar.
+type SimilarResponse struct {
+	Provider string             `json:"provider"`
+	Source   EnrichedParagraph  `json:"source"`
+	Results  []SimilarParagraph `json:"results"`
+}
+
+// similarityIndex guarda, por GlobalID, os vetores dos parágrafos servidos.
+type similarityIndex struct {
+	mu         sync.RWMutex
+	provider   string
+	vectors    map[int][]float32
+	paragraphs []EnrichedParagraph
+}
+
+func newSimilarityIndex() *similarityIndex {
+	return &similarityIndex{vectors: make(map[int][]float32)}
+}
+
+// rebuild associa os vetores do arquivo aos parágrafos, no provedor gravado no arquivo. Se
+// ele é o offline (ou o arquivo ainda não existe), os que faltam são calculados em memória;
+// com provedores pagos é preciso rodar -mode=embed.
+func (idx *similarityIndex) rebuild(paragraphs []EnrichedParagraph, filename string) {
+	set, err := loadEmbeddings(filename)
+	if err != nil {
+		log.Printf("Erro ao carregar %s: %v", filename, err)
+		set = embeddingSet{Vectors: make(map[string][]float32)}
+	}
+	if offline := (hashingEmbedder{dims: hashingEmbeddingDims}); set.Provider == "" || set.Provider == offline.Name() {
+		if _, err := updateEmbeddings(&set, offline, paragraphs); err != nil {
+			log.Printf("Erro ao calcular embeddings: %v", err)
+		}
+	}
+
+	vectors := make(map[int][]float32, len(paragraphs))
+	missing := 0
+	for _, p := range paragraphs {
+		if vec, ok := set.Vectors[paragraphTextHash(p.Paragraph)]; ok {
+			vectors[p.GlobalID] = vec
+		} else {
+			missing++
+		}
+	}
+	if missing > 0 {
+		log.Printf("AVISO: %d parágrafos sem embedding (%s). Execute -mode=embed.", missing, set.Provider)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.provider = set.Provider
+	idx.vectors = vectors
+	idx.paragraphs = paragraphs
+}
+
+// withEmbeddings retorna cópias dos parágrafos com Embedding preenchido pelo índice.
+func (idx *similarityIndex) withEmbeddings(paragraphs []EnrichedParagraph) []EnrichedParagraph {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	out := make([]EnrichedParagraph, len(paragraphs))
+	for i, p := range paragraphs {
+		p.Embedding = idx.vectors[p.GlobalID]
+		out[i] = p
+	}
+	return out
+}
+
+// Similar retorna os k parágrafos mais próximos (cosseno) de source. Por padrão ignora os
+// parágrafos da mesma reunião, para acompanhar a evolução do tema entre atas.
+func (idx *similarityIndex) Similar(source EnrichedParagraph, k int, sameMeeting bool) (SimilarResponse, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	query, ok := idx.vectors[source.GlobalID]
+	if !ok {
+		return SimilarResponse{}, false
+	}
+	results := make([]SimilarParagraph, 0, k)
+	for _, p := range idx.paragraphs {
+		if p.GlobalID == source.GlobalID || (!sameMeeting && p.MeetingNumber == source.MeetingNumber) {
+			continue
+		}
+		vec, ok := idx.vectors[p.GlobalID]
+		if !ok {
+			continue
+		}
+		results = append(results, SimilarParagraph{Score: dotProduct(query, vec), Paragraph: p})
+	}
+	slices.SortFunc(results, func(a, b SimilarParagraph) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Paragraph.GlobalID, b.Paragraph.GlobalID))
+	})
+	if len(results) > k {
+		results = results[:k]
+	}
+	return SimilarResponse{Provider: idx.provider, Source: source, Results: results}, true
+}
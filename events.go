@@ -0,0 +1,130 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Eventos do pipeline: scrape e enrich (jobs, agendador e modos de linha de comando), recargas
+// dos datasets e andamento dos jobs. Os assinantes (webhooks, GET /events) são chamados de
+// forma síncrona e devem apenas enfileirar o trabalho, para não atrasar quem publicou.
+
+// Tipos de evento
+const (
+	EventAtaScraped          = "ata.scraped"          // Nova ata salva pelo scrape (MeetingEvent)
+	EventEnrichmentCompleted = "enrichment.completed" // Parágrafos de uma reunião enriquecidos e salvos (MeetingEvent)
+	EventParagraphEnriched   = "paragraph.enriched"   // Um parágrafo analisado pelo Gemini (ParagraphEvent)
+	EventDatasetReloaded     = "dataset.reloaded"     // Stores recarregados do disco (ReloadResult)
+	EventJobStarted          = "job.started"          // Job (Job, sem logs)
+	EventJobProgress         = "job.progress"
+	EventJobFinished         = "job.finished"
+)
+
+var allEventTypes = []string{
+	EventAtaScraped, EventEnrichmentCompleted, EventParagraphEnriched, EventDatasetReloaded,
+	EventJobStarted, EventJobProgress, EventJobFinished,
+}
+
+// Event é um evento numerado em ordem de publicação. Data depende de Type (ver acima).
+type Event struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// MeetingEvent descreve a reunião a que o evento se refere.
+type MeetingEvent struct {
+	NumeroReuniao int    `json:"numero_reuniao"`
+	DataReuniao   string `json:"data_reuniao,omitempty"`
+	Titulo        string `json:"titulo,omitempty"`
+	URL           string `json:"url,omitempty"`
+	// SelicDecision é a meta da Selic decidida (% a.a.), quando extraída do texto.
+	SelicDecision *float64 `json:"selic_decision,omitempty"`
+	// Sentiment só existe quando a reunião tem parágrafos enriquecidos.
+	Sentiment *MeetingSentiment `json:"sentiment,omitempty"`
+}
+
+// ParagraphEvent é o payload de EventParagraphEnriched.
+type ParagraphEvent struct {
+	GlobalID      int              `json:"global_id"`
+	MeetingNumber int              `json:"meeting_number"`
+	ParagraphID   int              `json:"paragraph_id"`
+	Topics        []string         `json:"topics,omitempty"`
+	Prediction    GeminiPrediction `json:"prediction"`
+}
+
+// MeetingSentiment é o sentimento agregado da reunião, como em /timeseries.
+type MeetingSentiment struct {
+	Dollar     *float64 `json:"dollar"`
+	IPCA       *float64 `json:"ipca"`
+	Paragraphs int      `json:"paragraphs"`
+}
+
+// newMeetingEvent monta o payload a partir da ata e dos parágrafos enriquecidos da reunião.
+func newMeetingEvent(ata CopomAta, paragraphs []EnrichedParagraph) MeetingEvent {
+	ev := MeetingEvent{
+		NumeroReuniao: ata.NumeroReuniao,
+		DataReuniao:   ata.DataReuniao,
+		Titulo:        ata.Titulo,
+		URL:           ata.URL,
+	}
+	for _, f := range extractFacts(ata) {
+		if f.Kind == FactSelicDecision {
+			v := f.Value
+			ev.SelicDecision = &v
+			break
+		}
+	}
+	if len(paragraphs) > 0 {
+		ev.Sentiment = &MeetingSentiment{
+			Dollar:     trendSentiment(paragraphs, func(p GeminiPrediction) string { return p.DollarTrend }),
+			IPCA:       trendSentiment(paragraphs, func(p GeminiPrediction) string { return p.IPCATrend }),
+			Paragraphs: len(paragraphs),
+		}
+	}
+	return ev
+}
+
+// eventBus distribui os eventos aos assinantes. Um *eventBus nil descarta os eventos.
+type eventBus struct {
+	mu          sync.Mutex
+	nextID      int64
+	subscribers []func(Event)
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{nextID: 1}
+}
+
+// Subscribe registra fn para todos os eventos publicados a partir de agora.
+func (b *eventBus) Subscribe(fn func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, fn)
+}
+
+// Publish numera o evento e o entrega aos assinantes, na ordem de publicação.
+func (b *eventBus) Publish(eventType string, data any) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ev := Event{ID: b.nextID, Type: eventType, CreatedAt: time.Now(), Data: data}
+	b.nextID++
+	for _, fn := range b.subscribers {
+		fn(ev)
+	}
+}
+
+// meetingParagraphs filtra os parágrafos enriquecidos de uma reunião.
+func meetingParagraphs(paragraphs []EnrichedParagraph, meeting int) []EnrichedParagraph {
+	var out []EnrichedParagraph
+	for _, p := range paragraphs {
+		if p.MeetingNumber == meeting {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Formatos tabulares suportados pela exportação (CLI e API)
+const (
+	exportFormatCSV     = "csv"
+	exportFormatJSONL   = "jsonl"
+	exportFormatParquet = "parquet"
+)
+
+type columnKind int
+
+const (
+	columnString columnKind = iota
+	columnInt
+	columnFloat
+	columnBool
+)
+
+type exportColumn struct {
+	Name string
+	Kind columnKind
+}
+
+// exportTable é a visão achatada (uma linha por registro) usada por todos os formatos.
+type exportTable struct {
+	Name    string
+	Columns []exportColumn
+	Rows    [][]any
+}
+
+func isExportFormat(format string) bool {
+	return format == exportFormatCSV || format == exportFormatJSONL || format == exportFormatParquet
+}
+
+func exportContentType(format string) string {
+	switch format {
+	case exportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case exportFormatJSONL:
+		return "application/x-ndjson"
+	case exportFormatParquet:
+		return "application/vnd.apache.parquet"
+	}
+	return "application/octet-stream"
+}
+
+// atasTable achata as atas. O texto completo (conteudo) só entra se includeContent for true.
+func atasTable(atas []CopomAta, includeContent bool) *exportTable {
+	t := &exportTable{
+		Name: "atas",
+		Columns: []exportColumn{
+			{"numero_reuniao", columnInt},
+			{"url", columnString},
+			{"titulo", columnString},
+			{"data_reuniao", columnString},
+			{"valor_dolar", columnFloat},
+			{"valor_ipca", columnFloat},
+			{"falha_no_parse", columnBool},
+		},
+	}
+	if includeContent {
+		t.Columns = append(t.Columns, exportColumn{"conteudo", columnString})
+	}
+	for _, ata := range atas {
+		row := []any{ata.NumeroReuniao, ata.URL, ata.Titulo, ata.DataReuniao, ata.ValorDolar, ata.ValorIPCA, ata.FalhaNoParse}
+		if includeContent {
+			row = append(row, ata.Conteudo)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+// enrichedTable achata os parágrafos, expandindo Prediction em colunas prediction_*.
+// O texto do parágrafo só entra se includeContent for true, e o embedding (valores separados
+// por ";") se includeEmbedding for true.
+func enrichedTable(data []EnrichedParagraph, includeContent, includeEmbedding bool) *exportTable {
+	t := &exportTable{
+		Name: "enriched",
+		Columns: []exportColumn{
+			{"global_id", columnInt},
+			{"paragraph_id", columnInt},
+			{"meeting_number", columnInt},
+			{"url", columnString},
+			{"meeting_date", columnString},
+			{"dollar_value", columnFloat},
+			{"ipca_value", columnFloat},
+			{"prediction_dollar_trend", columnString},
+			{"prediction_ipca_trend", columnString},
+			{"prediction_reasoning", columnString},
+			{"topics", columnString}, // separados por ";"
+		},
+	}
+	if includeContent {
+		t.Columns = append(t.Columns, exportColumn{"paragraph", columnString})
+	}
+	if includeEmbedding {
+		t.Columns = append(t.Columns, exportColumn{"embedding", columnString})
+	}
+	for _, p := range data {
+		row := []any{
+			p.GlobalID, p.ParagraphID, p.MeetingNumber, p.URL, p.MeetingDate, p.DollarValue, p.IPCAValue,
+			p.Prediction.DollarTrend, p.Prediction.IPCATrend, p.Prediction.Reasoning,
+			strings.Join(p.Topics, ";"),
+		}
+		if includeContent {
+			row = append(row, p.Paragraph)
+		}
+		if includeEmbedding {
+			values := make([]string, len(p.Embedding))
+			for i, v := range p.Embedding {
+				values[i] = strconv.FormatFloat(float64(v), 'g', -1, 32)
+			}
+			row = append(row, strings.Join(values, ";"))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+func writeTable(w io.Writer, t *exportTable, format string) error {
+	switch format {
+	case exportFormatCSV:
+		return writeCSV(w, t)
+	case exportFormatJSONL:
+		return writeJSONL(w, t)
+	case exportFormatParquet:
+		return writeParquet(w, t)
+	}
+	return fmt.Errorf("formato de exportação desconhecido: %s", format)
+}
+
+func formatCell(v any) string {
+	switch val := v.(type) {
+	case int:
+		return strconv.Itoa(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case string:
+		return val
+	}
+	return fmt.Sprint(v)
+}
+
+func writeCSV(w io.Writer, t *exportTable) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		header[i] = c.Name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(t.Columns))
+	for _, row := range t.Rows {
+		for i, v := range row {
+			record[i] = formatCell(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeJSONL grava um objeto plano por linha, preservando a ordem das colunas.
+func writeJSONL(w io.Writer, t *exportTable) error {
+	var line bytes.Buffer
+	for _, row := range t.Rows {
+		line.Reset()
+		line.WriteByte('{')
+		for i, v := range row {
+			if i > 0 {
+				line.WriteByte(',')
+			}
+			key, _ := json.Marshal(t.Columns[i].Name)
+			val, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			line.Write(key)
+			line.WriteByte(':')
+			line.Write(val)
+		}
+		line.WriteString("}\n")
+		if _, err := w.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseExportFormats aceita "csv", "jsonl", "parquet" ou uma lista separada por vírgulas.
+func parseExportFormats(value string) ([]string, error) {
+	var formats []string
+	for _, f := range strings.Split(value, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		if !isExportFormat(f) {
+			return nil, fmt.Errorf("formato de exportação desconhecido: %s (use csv, jsonl ou parquet)", f)
+		}
+		formats = append(formats, f)
+	}
+	if len(formats) == 0 {
+		return nil, fmt.Errorf("nenhum formato de exportação informado")
+	}
+	return formats, nil
+}
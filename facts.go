@@ -0,0 +1,357 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Fatos numéricos extraídos do texto das atas (GET /atas/:numero/facts).
+//
+// A extração é feita por regras sobre as frases dos parágrafos numerados: cada frase é
+// classificada pelo tipo de fato (expectativa, projeção, Selic...) e seus percentuais são
+// associados aos períodos citados ("2025", "segundo trimestre de 2027"). A Tabela 1 das atas
+// recentes (projeções no cenário de referência) é lida separadamente.
+
+// Tipos de fato
+const (
+	FactInflationExpectation         = "inflation_expectation"          // Focus, por ano-calendário
+	FactInflationProjection          = "inflation_projection"           // Projeção do Copom para o IPCA
+	FactRelevantHorizonProjection    = "relevant_horizon_projection"    // Projeção do IPCA para o trimestre do horizonte relevante
+	FactFreePricesProjection         = "free_prices_projection"         // IPCA livres (Tabela 1)
+	FactAdministeredPricesProjection = "administered_prices_projection" // Preços administrados
+	FactSelicDecision                = "selic_decision"                 // Meta da Selic decidida na reunião
+	FactSelicPath                    = "selic_path"                     // Trajetória de juros suposta no cenário
+	FactGDPGrowth                    = "gdp_growth"                     // Variação do PIB citada no texto
+	FactExchangeRateAssumption       = "exchange_rate_assumption"       // Câmbio de partida do cenário de referência
+)
+
+var allFactKinds = []string{
+	FactInflationExpectation, FactInflationProjection, FactRelevantHorizonProjection,
+	FactFreePricesProjection, FactAdministeredPricesProjection, FactSelicDecision,
+	FactSelicPath, FactGDPGrowth, FactExchangeRateAssumption,
+}
+
+// Cenários das projeções
+const (
+	ScenarioReference   = "reference"
+	ScenarioAlternative = "alternative"
+)
+
+// AtaFact é um número extraído de uma ata, com o trecho de onde veio.
+type AtaFact struct {
+	Kind     string  `json:"kind"`
+	Period   string  `json:"period"` // "2025", "2027Q2" ou a data da reunião (YYYY-MM-DD) para decisões
+	Value    float64 `json:"value"`
+	Unit     string  `json:"unit"`               // "%", "% a.a." ou "R$/US$"
+	Scenario string  `json:"scenario,omitempty"` // reference | alternative (apenas projeções)
+	// Paragraph é o número do parágrafo da ata (0 para a Tabela 1) e Text a frase de origem.
+	Paragraph int    `json:"paragraph"`
+	Text      string `json:"text"`
+}
+
+// AtaFactsResponse é o corpo de GET /atas/:numero/facts.
+type AtaFactsResponse struct {
+	NumeroReuniao int       `json:"numero_reuniao"`
+	DataReuniao   string    `json:"data_reuniao,omitempty"`
+	Facts         []AtaFact `json:"facts"`
+}
+
+var (
+	// Regras aplicadas sobre o texto já normalizado por foldAccents
+	reFactPercent  = regexp.MustCompile(`(-?\d{1,3}(?:,\d+)?)\s?%`)
+	reFactPeriod   = regexp.MustCompile(`(?:(primeiro|segundo|terceiro|quarto|ultimo|[1-4]º) (?:trimestre|tri)(?: de| do ano de)? ((?:19|20)\d{2}))|\b((?:19|20)\d{2})\b`)
+	reFactLinkWord = regexp.MustCompile(`^\s*(?:a\.a\.,?\s*)?(?:para|em|no|na|ate)\s+(?:o\s+|a\s+)?`)
+	reFactGDP      = regexp.MustCompile(`\bpib\b|produto interno bruto`)
+	reFactExchange = regexp.MustCompile(`(?:r\$\s?|usd/brl\s)(\d,\d{2})`)
+	reFactTable    = regexp.MustCompile(`(?s)indice de precos(.*?)(?:notas de rodape|$)`)
+	reFactTableTok = regexp.MustCompile(`[1-4]º tri (?:19|20)\d{2}|(?:19|20)\d{2}|ipca administrados|ipca livres|ipca|-?\d+,\d+`)
+)
+
+var quarterNumbers = map[string]string{
+	"primeiro": "1", "segundo": "2", "terceiro": "3", "quarto": "4", "ultimo": "4",
+	"1º": "1", "2º": "2", "3º": "3", "4º": "4",
+}
+
+// parseFactKinds interpreta ?kind= (lista separada por vírgula); vazio não filtra.
+func parseFactKinds(spec string) (map[string]bool, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	kinds := make(map[string]bool)
+	for _, k := range strings.Split(spec, ",") {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if !slices.Contains(allFactKinds, k) {
+			return nil, fmt.Errorf("Tipo de fato '%s' desconhecido (use %s).", k, strings.Join(allFactKinds, ", "))
+		}
+		kinds[k] = true
+	}
+	return kinds, nil
+}
+
+type factMatch struct {
+	start, end int
+	text       string
+}
+
+// factPeriods retorna os períodos citados na frase, normalizados ("2024", "2024Q3").
+func factPeriods(folded string) ([]factMatch, []string) {
+	var matches []factMatch
+	var periods []string
+	for _, m := range reFactPeriod.FindAllStringSubmatchIndex(folded, -1) {
+		var period string
+		if m[6] >= 0 {
+			period = folded[m[6]:m[7]]
+		} else {
+			period = folded[m[4]:m[5]] + "Q" + quarterNumbers[folded[m[2]:m[3]]]
+		}
+		matches = append(matches, factMatch{m[0], m[1], period})
+		periods = append(periods, period)
+	}
+	return matches, periods
+}
+
+func parseDecimalBR(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	return v, err == nil
+}
+
+// classifyFactSentence decide o tipo de fato de uma frase (já normalizada); "" se nenhum.
+func classifyFactSentence(folded string) string {
+	switch {
+	case strings.Contains(folded, "precos administrados"):
+		return FactAdministeredPricesProjection
+	case strings.Contains(folded, "expectativas de inflacao para"):
+		return FactInflationExpectation
+	case strings.Contains(folded, "projec") && strings.Contains(folded, "inflacao"):
+		return FactInflationProjection
+	case reFactGDP.MatchString(folded):
+		return FactGDPGrowth
+	case strings.Contains(folded, "juros") && strings.Contains(folded, "termina"):
+		return FactSelicPath
+	case strings.Contains(folded, "decidiu") && strings.Contains(folded, "a.a.") &&
+		(strings.Contains(folded, "taxa basica de juros") || strings.Contains(folded, "taxa selic")):
+		return FactSelicDecision
+	case strings.Contains(folded, "taxa de cambio parte de"):
+		return FactExchangeRateAssumption
+	}
+	return ""
+}
+
+// pairValuesWithPeriods associa cada percentual da frase a um período, na ordem:
+//  1. todos os valores seguidos do período ("4,6% para 2023, 3,5% para 2024");
+//  2. listas de mesmo tamanho ("para 2025 e 2026 ... 4,5% e 4,2%, respectivamente");
+//  3. com "respectivamente" e períodos sobrando, os últimos períodos citados;
+//  4. com mais valores que períodos, apenas os valores seguidos do período.
+//
+// Retorna o período de cada valor ("" quando não associado); casos ambíguos não geram fatos.
+func pairValuesWithPeriods(folded string, values []factMatch) []string {
+	periodMatches, periods := factPeriods(folded)
+	periodAt := make(map[int]string, len(periodMatches))
+	for _, pm := range periodMatches {
+		periodAt[pm.start] = pm.text
+	}
+
+	followed := make([]string, len(values))
+	allFollowed := len(values) > 0
+	for i, v := range values {
+		if link := reFactLinkWord.FindString(folded[v.end:]); link != "" {
+			followed[i] = periodAt[v.end+len(link)]
+		}
+		allFollowed = allFollowed && followed[i] != ""
+	}
+
+	switch {
+	case allFollowed:
+		return followed
+	case len(periods) == len(values):
+		return periods
+	case len(periods) > len(values) && strings.Contains(folded, "respectivamente"):
+		return periods[len(periods)-len(values):]
+	case len(periods) < len(values):
+		return followed
+	}
+	return make([]string, len(values))
+}
+
+// sentenceFacts extrai os fatos de uma frase.
+func sentenceFacts(sentence string, paragraph int, meetingDate string) []AtaFact {
+	folded := foldAccents(sentence)
+	kind := classifyFactSentence(folded)
+	if kind == "" {
+		return nil
+	}
+	text := normalizeSpace(sentence)
+
+	if kind == FactExchangeRateAssumption {
+		m := reFactExchange.FindStringSubmatch(folded)
+		if m == nil {
+			return nil
+		}
+		v, ok := parseDecimalBR(m[1])
+		if !ok {
+			return nil
+		}
+		return []AtaFact{{Kind: kind, Period: meetingDate, Value: v, Unit: "R$/US$", Paragraph: paragraph, Text: text}}
+	}
+
+	var values []factMatch
+	for _, m := range reFactPercent.FindAllStringSubmatchIndex(folded, -1) {
+		values = append(values, factMatch{m[0], m[1], folded[m[2]:m[3]]})
+	}
+
+	if kind == FactSelicDecision {
+		// A meta decidida é o percentual seguido de "a.a." (ignora "em 0,50 ponto percentual")
+		for _, v := range values {
+			if strings.HasPrefix(strings.TrimSpace(folded[v.end:]), "a.a.") {
+				value, _ := parseDecimalBR(v.text)
+				return []AtaFact{{Kind: kind, Period: meetingDate, Value: value, Unit: "% a.a.", Paragraph: paragraph, Text: text}}
+			}
+		}
+		return nil
+	}
+
+	var scenario string
+	switch kind {
+	case FactInflationProjection, FactAdministeredPricesProjection, FactSelicPath:
+		scenario = ScenarioReference
+		if strings.Contains(folded, "alternativ") || strings.Contains(folded, "em tal cenario") {
+			scenario = ScenarioAlternative
+		}
+	}
+	unit := "%"
+	if kind == FactSelicPath {
+		unit = "% a.a."
+	}
+
+	var facts []AtaFact
+	for i, period := range pairValuesWithPeriods(folded, values) {
+		value, ok := parseDecimalBR(values[i].text)
+		if period == "" || !ok {
+			continue
+		}
+		factKind := kind
+		if kind == FactInflationProjection && strings.Contains(period, "Q") {
+			factKind = FactRelevantHorizonProjection
+		}
+		facts = append(facts, AtaFact{
+			Kind: factKind, Period: period, Value: value, Unit: unit,
+			Scenario: scenario, Paragraph: paragraph, Text: text,
+		})
+	}
+	return facts
+}
+
+// tableFacts lê a Tabela 1 ("Projeções de inflação no cenário de referência"): cabeçalho
+// com os períodos seguido das linhas IPCA, IPCA livres e IPCA administrados.
+func tableFacts(content string) []AtaFact {
+	m := reFactTable.FindStringSubmatch(foldAccents(content))
+	if m == nil {
+		return nil
+	}
+	rowKinds := map[string]string{
+		"ipca":               FactInflationProjection,
+		"ipca livres":        FactFreePricesProjection,
+		"ipca administrados": FactAdministeredPricesProjection,
+	}
+
+	var periods []string
+	var facts []AtaFact
+	row, col := "", 0
+	for _, tok := range reFactTableTok.FindAllString(m[1], -1) {
+		switch {
+		case rowKinds[tok] != "":
+			row, col = tok, 0
+		case row == "" && strings.Contains(tok, "º tri"):
+			fields := strings.Fields(tok)
+			periods = append(periods, fields[2]+"Q"+quarterNumbers[fields[0]])
+		case row == "" && !strings.Contains(tok, ","):
+			periods = append(periods, tok)
+		case row != "" && strings.Contains(tok, ","):
+			if col >= len(periods) {
+				continue
+			}
+			value, ok := parseDecimalBR(tok)
+			if !ok {
+				continue
+			}
+			kind := rowKinds[row]
+			if kind == FactInflationProjection && strings.Contains(periods[col], "Q") {
+				kind = FactRelevantHorizonProjection
+			}
+			facts = append(facts, AtaFact{
+				Kind: kind, Period: periods[col], Value: value, Unit: "%",
+				Scenario: ScenarioReference, Text: "Tabela 1: " + row,
+			})
+			col++
+		}
+	}
+	return facts
+}
+
+// extractFacts extrai os fatos de uma ata. Quando o mesmo número (tipo, período, cenário)
+// aparece mais de uma vez, fica a primeira ocorrência no texto (a Tabela 1 vem por último).
+func extractFacts(ata CopomAta) []AtaFact {
+	if ata.Conteudo == "" {
+		return nil
+	}
+	var all []AtaFact
+	for i, paragraph := range numberedParagraphs(ata) {
+		for _, sentence := range splitSentences(paragraph) {
+			all = append(all, sentenceFacts(sentence, i+1, ata.DataReuniao)...)
+		}
+	}
+	all = append(all, tableFacts(ata.Conteudo)...)
+
+	seen := make(map[string]bool, len(all))
+	facts := make([]AtaFact, 0, len(all))
+	for _, f := range all {
+		key := f.Kind + "|" + f.Period + "|" + f.Scenario
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		facts = append(facts, f)
+	}
+	return facts
+}
+
+// factIndex guarda os fatos extraídos de cada reunião, reconstruído a cada recarga.
+type factIndex struct {
+	mu        sync.RWMutex
+	byMeeting map[int][]AtaFact
+}
+
+func newFactIndex() *factIndex {
+	return &factIndex{byMeeting: make(map[int][]AtaFact)}
+}
+
+// rebuild extrai os fatos fora do lock e troca o índice de uma só vez.
+func (idx *factIndex) rebuild(atas []CopomAta) {
+	byMeeting := make(map[int][]AtaFact, len(atas))
+	for _, ata := range atas {
+		if ata.NumeroReuniao != 0 {
+			byMeeting[ata.NumeroReuniao] = extractFacts(ata)
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.byMeeting = byMeeting
+}
+
+// Facts retorna os fatos de uma reunião, opcionalmente filtrados por tipo.
+func (idx *factIndex) Facts(meeting int, kinds map[string]bool) []AtaFact {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	facts := make([]AtaFact, 0, len(idx.byMeeting[meeting]))
+	for _, f := range idx.byMeeting[meeting] {
+		if kinds == nil || kinds[f.Kind] {
+			facts = append(facts, f)
+		}
+	}
+	return facts
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Feeds Atom (GET /feed.atom) e RSS 2.0 (GET /feed.rss) das atas: uma entrada por reunião,
+// da mais recente para a mais antiga, com a decisão sobre a Selic e os primeiros parágrafos.
+
+const (
+	defaultFeedLimit = 20
+	maxFeedLimit     = 100
+	// feedParagraphs é quantos parágrafos numerados da ata entram no conteúdo da entrada.
+	feedParagraphs = 3
+	feedTitle      = "Atas do Copom"
+	feedSiteURL    = "https://www.bcb.gov.br/publicacoes/atascopom"
+)
+
+// feedEntry é uma reunião já resumida, comum aos dois formatos.
+type feedEntry struct {
+	Title   string
+	Link    string
+	Date    time.Time
+	Summary string // texto puro
+	Content string // HTML
+}
+
+// selicDecisionSummary descreve a decisão comparando com a meta da reunião anterior.
+func selicDecisionSummary(current, previous *AtaFact) string {
+	if current == nil {
+		return ""
+	}
+	if previous == nil {
+		return fmt.Sprintf("Selic em %s%% a.a.", formatDecimalBR(current.Value))
+	}
+	delta := math.Round((current.Value-previous.Value)*100) / 100
+	switch {
+	case delta > 0:
+		return fmt.Sprintf("Selic elevada em %s p.p., para %s%% a.a.", formatDecimalBR(delta), formatDecimalBR(current.Value))
+	case delta < 0:
+		return fmt.Sprintf("Selic reduzida em %s p.p., para %s%% a.a.", formatDecimalBR(-delta), formatDecimalBR(current.Value))
+	default:
+		return fmt.Sprintf("Selic mantida em %s%% a.a.", formatDecimalBR(current.Value))
+	}
+}
+
+// formatDecimalBR formata com duas casas e vírgula decimal ("15,00").
+func formatDecimalBR(v float64) string {
+	return strings.Replace(fmt.Sprintf("%.2f", v), ".", ",", 1)
+}
+
+// buildFeedEntries monta as limit entradas mais recentes. Atas sem data ou sem conteúdo
+// ficam de fora; a decisão vem do índice de fatos.
+func buildFeedEntries(atas []CopomAta, facts *factIndex, limit int) []feedEntry {
+	atas = slices.DeleteFunc(slices.Clone(atas), func(a CopomAta) bool {
+		return a.NumeroReuniao == 0 || a.DataReuniao == "" || a.Conteudo == ""
+	})
+	slices.SortFunc(atas, func(a, b CopomAta) int {
+		return cmp.Or(cmp.Compare(a.DataReuniao, b.DataReuniao), cmp.Compare(a.NumeroReuniao, b.NumeroReuniao))
+	})
+
+	decision := func(meeting int) *AtaFact {
+		found := facts.Facts(meeting, map[string]bool{FactSelicDecision: true})
+		if len(found) == 0 {
+			return nil
+		}
+		return &found[0]
+	}
+
+	entries := make([]feedEntry, 0, min(limit, len(atas)))
+	for i := len(atas) - 1; i >= 0 && len(entries) < limit; i-- {
+		ata := atas[i]
+		date, err := time.Parse("2006-01-02", ata.DataReuniao)
+		if err != nil {
+			continue
+		}
+		current := decision(ata.NumeroReuniao)
+		var previous *AtaFact
+		if i > 0 {
+			previous = decision(atas[i-1].NumeroReuniao)
+		}
+		summary := selicDecisionSummary(current, previous)
+
+		var content strings.Builder
+		if current != nil {
+			fmt.Fprintf(&content, "<p><strong>%s</strong> %s</p>\n", html.EscapeString(summary), html.EscapeString(current.Text))
+		}
+		paragraphs := numberedParagraphs(ata)
+		for _, p := range paragraphs[:min(feedParagraphs, len(paragraphs))] {
+			fmt.Fprintf(&content, "<p>%s</p>\n", html.EscapeString(p))
+		}
+		fmt.Fprintf(&content, "<p><a href=\"%s\">Leia a ata completa no site do Banco Central</a></p>", html.EscapeString(ata.URL))
+
+		if summary == "" && len(paragraphs) > 0 {
+			summary = truncateRunes(paragraphs[0], 280)
+		}
+		title := ata.Titulo
+		if title == "" {
+			title = fmt.Sprintf("%dª Reunião - Comitê de Política Monetária", ata.NumeroReuniao)
+		}
+		entries = append(entries, feedEntry{
+			Title:   title,
+			Link:    ata.URL,
+			Date:    date,
+			Summary: summary,
+			Content: content.String(),
+		})
+	}
+	return entries
+}
+
+// truncateRunes corta s em n caracteres, terminando com reticências.
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(r[:n])) + "…"
+}
+
+// --- Atom ---
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title     string   `xml:"title"`
+	ID        string   `xml:"id"`
+	Link      atomLink `xml:"link"`
+	Published string   `xml:"published"`
+	Updated   string   `xml:"updated"`
+	Summary   atomText `xml:"summary"`
+	Content   atomText `xml:"content"`
+}
+
+// renderAtom gera o feed Atom; selfURL é a URL absoluta do próprio feed.
+func renderAtom(entries []feedEntry, selfURL string) ([]byte, error) {
+	feed := atomFeed{
+		Title: feedTitle,
+		ID:    selfURL,
+		Links: []atomLink{
+			{Href: selfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: feedSiteURL, Rel: "alternate", Type: "text/html"},
+		},
+		Author:  atomAuthor{Name: "Banco Central do Brasil"},
+		Entries: make([]atomEntry, 0, len(entries)),
+	}
+	updated := time.Unix(0, 0).UTC()
+	for _, e := range entries {
+		date := e.Date.Format(time.RFC3339)
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:     e.Title,
+			ID:        e.Link,
+			Link:      atomLink{Href: e.Link, Rel: "alternate", Type: "text/html"},
+			Published: date,
+			Updated:   date,
+			Summary:   atomText{Type: "text", Body: e.Summary},
+			Content:   atomText{Type: "html", Body: e.Content},
+		})
+		if e.Date.After(updated) {
+			updated = e.Date
+		}
+	}
+	feed.Updated = updated.Format(time.RFC3339)
+	return marshalFeed(feed)
+}
+
+// --- RSS 2.0 ---
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+// renderRSS gera o feed RSS 2.0; a descrição de cada item é o mesmo HTML do Atom.
+func renderRSS(entries []feedEntry, selfURL string) ([]byte, error) {
+	feed := rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       feedTitle,
+			Link:        feedSiteURL,
+			Description: "Atas das reuniões do Comitê de Política Monetária (Copom) do Banco Central do Brasil",
+			Language:    "pt-BR",
+			AtomLink:    atomLink{Href: selfURL, Rel: "self", Type: "application/rss+xml"},
+			Items:       make([]rssItem, 0, len(entries)),
+		},
+	}
+	if len(entries) > 0 {
+		feed.Channel.LastBuildDate = entries[0].Date.Format(time.RFC1123Z)
+	}
+	for _, e := range entries {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			Description: e.Content,
+			GUID:        rssGUID{IsPermaLink: true, Value: e.Link},
+			PubDate:     e.Date.Format(time.RFC1123Z),
+		})
+	}
+	return marshalFeed(feed)
+}
+
+func marshalFeed(feed any) ([]byte, error) {
+	out, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Parsing de query params ---
+
+func queryInt(c *gin.Context, name string) (*int, error) {
+	val := c.Query(name)
+	if val == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return nil, fmt.Errorf("Parâmetro '%s' inválido.", name)
+	}
+	return &n, nil
+}
+
+func queryFloat(c *gin.Context, name string) (*float64, error) {
+	val := c.Query(name)
+	if val == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(strings.Replace(val, ",", ".", 1), 64)
+	if err != nil {
+		return nil, fmt.Errorf("Parâmetro '%s' inválido.", name)
+	}
+	return &f, nil
+}
+
+func queryBool(c *gin.Context, name string) (*bool, error) {
+	val := c.Query(name)
+	if val == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return nil, fmt.Errorf("Parâmetro '%s' inválido: use true ou false.", name)
+	}
+	return &b, nil
+}
+
+// queryDate valida datas no mesmo formato de DataReuniao/MeetingDate (YYYY-MM-DD), que
+// podem ser comparadas como string.
+func queryDate(c *gin.Context, name string) (string, error) {
+	val := c.Query(name)
+	if val == "" {
+		return "", nil
+	}
+	if _, err := time.Parse("2006-01-02", val); err != nil {
+		return "", fmt.Errorf("Parâmetro '%s' inválido: use o formato YYYY-MM-DD.", name)
+	}
+	return val, nil
+}
+
+// queryTrends aceita um ou mais rótulos separados por vírgula (ex: "SUBIR,NEUTRO").
+func queryTrends(c *gin.Context, name string) (map[string]bool, error) {
+	val := c.Query(name)
+	if val == "" {
+		return nil, nil
+	}
+	trends := make(map[string]bool)
+	for _, t := range strings.Split(val, ",") {
+		t = strings.ToUpper(strings.TrimSpace(t))
+		if !isValidTrend(t) {
+			return nil, fmt.Errorf("Parâmetro '%s' inválido: use SUBIR, DESCER ou NEUTRO.", name)
+		}
+		trends[t] = true
+	}
+	return trends, nil
+}
+
+// queryTopics lê uma lista de tópicos separados por vírgula (ver allTopics).
+func queryTopics(c *gin.Context, name string) (map[string]bool, error) {
+	val := c.Query(name)
+	if val == "" {
+		return nil, nil
+	}
+	topics := make(map[string]bool)
+	for _, t := range strings.Split(val, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !isValidTopic(t) {
+			return nil, fmt.Errorf("Parâmetro '%s' inválido: use %s.", name, strings.Join(allTopics, ", "))
+		}
+		topics[t] = true
+	}
+	return topics, nil
+}
+
+func inRange[T cmp.Ordered](v T, min, max *T) bool {
+	return (min == nil || v >= *min) && (max == nil || v <= *max)
+}
+
+// --- Ordenação ---
+
+// sortKey é um critério de ordenação (campo + direção) montado a partir de ?sort=.
+type sortKey[T any] struct {
+	compare func(a, b T) int
+	desc    bool
+}
+
+// parseSort interpreta "campo1,-campo2" ("-" = decrescente) usando os campos permitidos.
+func parseSort[T any](spec string, fields map[string]func(a, b T) int) ([]sortKey[T], error) {
+	var keys []sortKey[T]
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+		compare, ok := fields[field]
+		if !ok {
+			allowed := make([]string, 0, len(fields))
+			for name := range fields {
+				allowed = append(allowed, name)
+			}
+			slices.Sort(allowed)
+			return nil, fmt.Errorf("Parâmetro 'sort' inválido: campo '%s' desconhecido (use %s).", field, strings.Join(allowed, ", "))
+		}
+		keys = append(keys, sortKey[T]{compare: compare, desc: desc})
+	}
+	return keys, nil
+}
+
+// compareBy combina os critérios de ordenação em uma única função de comparação.
+func compareBy[T any](keys []sortKey[T]) func(a, b T) int {
+	return func(a, b T) int {
+		for _, k := range keys {
+			if c := k.compare(a, b); c != 0 {
+				if k.desc {
+					return -c
+				}
+				return c
+			}
+		}
+		return 0
+	}
+}
+
+// sortItems ordena uma cópia de items; empates mantêm a ordem original.
+func sortItems[T any](items []T, keys []sortKey[T]) []T {
+	if len(keys) == 0 {
+		return items
+	}
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, compareBy(keys))
+	return sorted
+}
+
+// --- Filtros de /enriched ---
+
+var enrichedSortFields = map[string]func(a, b EnrichedParagraph) int{
+	"global_id":      func(a, b EnrichedParagraph) int { return cmp.Compare(a.GlobalID, b.GlobalID) },
+	"paragraph_id":   func(a, b EnrichedParagraph) int { return cmp.Compare(a.ParagraphID, b.ParagraphID) },
+	"meeting_number": func(a, b EnrichedParagraph) int { return cmp.Compare(a.MeetingNumber, b.MeetingNumber) },
+	"meeting_date":   func(a, b EnrichedParagraph) int { return cmp.Compare(a.MeetingDate, b.MeetingDate) },
+	"dollar_value":   func(a, b EnrichedParagraph) int { return cmp.Compare(a.DollarValue, b.DollarValue) },
+	"ipca_value":     func(a, b EnrichedParagraph) int { return cmp.Compare(a.IPCAValue, b.IPCAValue) },
+	"dollar_trend": func(a, b EnrichedParagraph) int {
+		return cmp.Compare(a.Prediction.DollarTrend, b.Prediction.DollarTrend)
+	},
+	"ipca_trend": func(a, b EnrichedParagraph) int { return cmp.Compare(a.Prediction.IPCATrend, b.Prediction.IPCATrend) },
+	"url":        func(a, b EnrichedParagraph) int { return cmp.Compare(a.URL, b.URL) },
+	"paragraph":  func(a, b EnrichedParagraph) int { return cmp.Compare(a.Paragraph, b.Paragraph) },
+	"reasoning": func(a, b EnrichedParagraph) int {
+		return cmp.Compare(a.Prediction.Reasoning, b.Prediction.Reasoning)
+	},
+}
+
+// enrichedFilter reúne os filtros de GET /enriched. Campos nil/vazios não filtram.
+type enrichedFilter struct {
+	MeetingFrom *int
+	MeetingTo   *int
+	DateFrom    string
+	DateTo      string
+	DollarTrend map[string]bool
+	IPCATrend   map[string]bool
+	DollarMin   *float64
+	DollarMax   *float64
+	IPCAMin     *float64
+	IPCAMax     *float64
+	Contains    string // já normalizado com foldAccents
+	Topics      map[string]bool
+}
+
+func parseEnrichedFilter(c *gin.Context) (enrichedFilter, error) {
+	var f enrichedFilter
+	var err error
+	if f.MeetingFrom, err = queryInt(c, "meeting_from"); err != nil {
+		return f, err
+	}
+	if f.MeetingTo, err = queryInt(c, "meeting_to"); err != nil {
+		return f, err
+	}
+	if f.DateFrom, err = queryDate(c, "from"); err != nil {
+		return f, err
+	}
+	if f.DateTo, err = queryDate(c, "to"); err != nil {
+		return f, err
+	}
+	if f.DollarTrend, err = queryTrends(c, "dollar_trend"); err != nil {
+		return f, err
+	}
+	if f.IPCATrend, err = queryTrends(c, "ipca_trend"); err != nil {
+		return f, err
+	}
+	if f.DollarMin, err = queryFloat(c, "dollar_min"); err != nil {
+		return f, err
+	}
+	if f.DollarMax, err = queryFloat(c, "dollar_max"); err != nil {
+		return f, err
+	}
+	if f.IPCAMin, err = queryFloat(c, "ipca_min"); err != nil {
+		return f, err
+	}
+	if f.IPCAMax, err = queryFloat(c, "ipca_max"); err != nil {
+		return f, err
+	}
+	if f.Topics, err = queryTopics(c, "topic"); err != nil {
+		return f, err
+	}
+	f.Contains = foldAccents(strings.TrimSpace(c.Query("contains")))
+	return f, nil
+}
+
+func (f enrichedFilter) match(p EnrichedParagraph) bool {
+	if !inRange(p.MeetingNumber, f.MeetingFrom, f.MeetingTo) {
+		return false
+	}
+	if f.DateFrom != "" && p.MeetingDate < f.DateFrom {
+		return false
+	}
+	if f.DateTo != "" && p.MeetingDate > f.DateTo {
+		return false
+	}
+	if f.DollarTrend != nil && !f.DollarTrend[p.Prediction.DollarTrend] {
+		return false
+	}
+	if f.IPCATrend != nil && !f.IPCATrend[p.Prediction.IPCATrend] {
+		return false
+	}
+	if !inRange(p.DollarValue, f.DollarMin, f.DollarMax) || !inRange(p.IPCAValue, f.IPCAMin, f.IPCAMax) {
+		return false
+	}
+	if f.Topics != nil && !hasAnyTopic(p, f.Topics) {
+		return false
+	}
+	if f.Contains != "" && !strings.Contains(foldAccents(p.Paragraph), f.Contains) {
+		return false
+	}
+	return true
+}
+
+func (f enrichedFilter) apply(data []EnrichedParagraph) []EnrichedParagraph {
+	filtered := make([]EnrichedParagraph, 0, len(data))
+	for _, p := range data {
+		if f.match(p) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// --- Filtros de /atas ---
+
+var ataSortFields = map[string]func(a, b CopomAta) int{
+	"numero_reuniao": func(a, b CopomAta) int { return cmp.Compare(a.NumeroReuniao, b.NumeroReuniao) },
+	"data_reuniao":   func(a, b CopomAta) int { return cmp.Compare(a.DataReuniao, b.DataReuniao) },
+	"valor_dolar":    func(a, b CopomAta) int { return cmp.Compare(a.ValorDolar, b.ValorDolar) },
+	"valor_ipca":     func(a, b CopomAta) int { return cmp.Compare(a.ValorIPCA, b.ValorIPCA) },
+	"titulo":         func(a, b CopomAta) int { return cmp.Compare(a.Titulo, b.Titulo) },
+}
+
+// ataFilter reúne os filtros de GET /atas e /atas/numeros. Campos nil/vazios não filtram.
+type ataFilter struct {
+	DateFrom     string
+	DateTo       string
+	Year         *int
+	HasContent   *bool
+	FalhaNoParse *bool
+}
+
+func parseAtaFilter(c *gin.Context) (ataFilter, error) {
+	var f ataFilter
+	var err error
+	if f.DateFrom, err = queryDate(c, "from"); err != nil {
+		return f, err
+	}
+	if f.DateTo, err = queryDate(c, "to"); err != nil {
+		return f, err
+	}
+	if f.Year, err = queryInt(c, "year"); err != nil {
+		return f, err
+	}
+	if f.HasContent, err = queryBool(c, "has_content"); err != nil {
+		return f, err
+	}
+	if f.FalhaNoParse, err = queryBool(c, "falha_no_parse"); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+func (f ataFilter) match(ata CopomAta) bool {
+	if f.DateFrom != "" && ata.DataReuniao < f.DateFrom {
+		return false
+	}
+	if f.DateTo != "" && ata.DataReuniao > f.DateTo {
+		return false
+	}
+	if f.Year != nil && !strings.HasPrefix(ata.DataReuniao, strconv.Itoa(*f.Year)+"-") {
+		return false
+	}
+	if f.HasContent != nil && (ata.Conteudo != "") != *f.HasContent {
+		return false
+	}
+	if f.FalhaNoParse != nil && ata.FalhaNoParse != *f.FalhaNoParse {
+		return false
+	}
+	return true
+}
+
+func (f ataFilter) apply(atas []CopomAta) []CopomAta {
+	filtered := make([]CopomAta, 0, len(atas))
+	for _, ata := range atas {
+		if f.match(ata) {
+			filtered = append(filtered, ata)
+		}
+	}
+	return filtered
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -38,15 +39,24 @@ type GeminiResponse struct {
 	} `json:"candidates"`
 }
 
-func callGeminiAPI(paragraph string, dollar float64, ipca float64) (GeminiPrediction, error) {
+// geminiAnalysis é a resposta do prompt de enriquecimento: a predição mais os tópicos
+// do parágrafo (validados depois por paragraphTopics).
+type geminiAnalysis struct {
+	GeminiPrediction
+	Topics []string `json:"topics"`
+}
+
+func callGeminiAPI(ctx context.Context, paragraph string, dollar float64, ipca float64) (geminiAnalysis, error) {
 	apiKey := os.Getenv("GEMINI_API_KEY")
 	if apiKey == "" {
-		return GeminiPrediction{}, fmt.Errorf("GEMINI_API_KEY não definida")
+		return geminiAnalysis{}, fmt.Errorf("GEMINI_API_KEY não definida")
 	}
 
 	prompt := fmt.Sprintf(`
 Analise o seguinte parágrafo da Ata do COPOM e os dados econômicos fornecidos.
 Faça uma predição de tendência para o Dólar e para o IPCA (inflação) com base no tom e conteúdo do texto.
+Classifique também o parágrafo em um ou mais tópicos, usando apenas os identificadores abaixo:
+%s
 
 Dados:
 - Dólar PTAX (dia anterior à reunião): %.4f
@@ -57,9 +67,10 @@ Responda APENAS com um JSON no seguinte formato, sem markdown ou explicações a
 {
   "dollar_trend": "SUBIR" | "DESCER" | "NEUTRO",
   "ipca_trend": "SUBIR" | "DESCER" | "NEUTRO",
-  "reasoning": "Breve explicação do porquê (máx 1 frase)"
+  "reasoning": "Breve explicação do porquê (máx 1 frase)",
+  "topics": ["identificador", ...]
 }
-`, dollar, ipca, paragraph)
+`, topicPromptList(), dollar, ipca, paragraph)
 
 	reqBody := GeminiRequest{
 		Contents: []GeminiContent{
@@ -73,13 +84,13 @@ Responda APENAS com um JSON no seguinte formato, sem markdown ou explicações a
 
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return GeminiPrediction{}, err
+		return geminiAnalysis{}, err
 	}
 
 	url := fmt.Sprintf("%s?key=%s", geminiAPIURL, apiKey)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return GeminiPrediction{}, err
+		return geminiAnalysis{}, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -87,22 +98,22 @@ Responda APENAS com um JSON no seguinte formato, sem markdown ou explicações a
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return GeminiPrediction{}, err
+		return geminiAnalysis{}, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return GeminiPrediction{}, fmt.Errorf("erro na API Gemini (status %d): %s", resp.StatusCode, string(bodyBytes))
+		return geminiAnalysis{}, fmt.Errorf("erro na API Gemini (status %d): %s", resp.StatusCode, string(bodyBytes))
 	}
 
 	var geminiResp GeminiResponse
 	if err := json.NewDecoder(resp.Body).Decode(&geminiResp); err != nil {
-		return GeminiPrediction{}, err
+		return geminiAnalysis{}, err
 	}
 
 	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
-		return GeminiPrediction{}, fmt.Errorf("resposta vazia do Gemini")
+		return geminiAnalysis{}, fmt.Errorf("resposta vazia do Gemini")
 	}
 
 	responseText := geminiResp.Candidates[0].Content.Parts[0].Text
@@ -118,11 +129,84 @@ Responda APENAS com um JSON no seguinte formato, sem markdown ou explicações a
 	}
 	responseText = strings.TrimSpace(responseText)
 
-	var prediction GeminiPrediction
-	if err := json.Unmarshal([]byte(responseText), &prediction); err != nil {
+	var analysis geminiAnalysis
+	if err := json.Unmarshal([]byte(responseText), &analysis); err != nil {
 		log.Printf("Erro ao parsear JSON do Gemini: %s", responseText)
-		return GeminiPrediction{Reasoning: "Erro no parse da resposta"}, nil // Retorna vazio mas não erro fatal
+		return geminiAnalysis{GeminiPrediction: GeminiPrediction{Reasoning: "Erro no parse da resposta"}}, nil // Retorna vazio mas não erro fatal
+	}
+
+	return analysis, nil
+}
+
+const geminiEmbeddingModel = "text-embedding-004"
+const geminiEmbeddingURL = "https://generativelanguage.googleapis.com/v1beta/models/" + geminiEmbeddingModel + ":batchEmbedContents"
+
+// Structs para a API de embeddings em lote do Gemini
+type GeminiEmbedRequest struct {
+	Requests []GeminiEmbedContentRequest `json:"requests"`
+}
+
+type GeminiEmbedContentRequest struct {
+	Model   string        `json:"model"`
+	Content GeminiContent `json:"content"`
+}
+
+type GeminiEmbedResponse struct {
+	Embeddings []struct {
+		Values []float32 `json:"values"`
+	} `json:"embeddings"`
+}
+
+// geminiEmbedder implementa embeddingProvider com o modelo de embeddings do Gemini.
+type geminiEmbedder struct {
+	apiKey string
+}
+
+func (g geminiEmbedder) Name() string {
+	return "gemini/" + geminiEmbeddingModel
+}
+
+func (g geminiEmbedder) Embed(texts []string) ([][]float32, error) {
+	reqBody := GeminiEmbedRequest{Requests: make([]GeminiEmbedContentRequest, len(texts))}
+	for i, text := range texts {
+		reqBody.Requests[i] = GeminiEmbedContentRequest{
+			Model:   "models/" + geminiEmbeddingModel,
+			Content: GeminiContent{Parts: []GeminiPart{{Text: text}}},
+		}
 	}
 
-	return prediction, nil
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s?key=%s", geminiEmbeddingURL, g.apiKey)
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("erro na API Gemini (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var embedResp GeminiEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return nil, err
+	}
+
+	vectors := make([][]float32, len(embedResp.Embeddings))
+	for i, e := range embedResp.Embeddings {
+		vectors[i] = e.Values
+	}
+	return vectors, nil
 }
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"sync"
+)
+
+// Rastreador de forward guidance (GET /guidance): detecta nas atas as frases de sinalização
+// do Copom, normaliza cada uma para um identificador estável e monta a linha do tempo de
+// quando cada sinalização aparece e desaparece.
+
+// guidancePhrase é uma sinalização conhecida. Pattern roda sobre o texto já normalizado
+// por foldAccents e deve ser específico o bastante para não casar com descrições de outros
+// bancos centrais ("sua interrupção em outros países").
+type guidancePhrase struct {
+	ID      string
+	Label   string
+	Pattern *regexp.Regexp
+}
+
+var guidancePhrases = []guidancePhrase{
+	{"interrupcao_ciclo", "Interrupção do ciclo", regexp.MustCompile(`interrupcao (?:do|no) ciclo`)},
+	{"periodo_bastante_prolongado", "Período bastante prolongado", regexp.MustCompile(`periodo bastante prolongado`)},
+	{"manutencao_periodo_prolongado", "Manutenção dos juros por período (suficientemente) prolongado",
+		regexp.MustCompile(`manutencao da taxa (?:basica )?de juros por (?:um )?periodo (?:suficientemente )?prolongado`)},
+	{"nao_hesitara_retomar", "Não hesitará em retomar o ciclo", regexp.MustCompile(`nao hesitara em retomar`)},
+	{"nao_hesitara_elevar", "Não hesitará em elevar/prosseguir no ciclo", regexp.MustCompile(`nao hesitara em (?:elevar|prosseguir)`)},
+	{"mesma_magnitude", "Ajuste de mesma magnitude nas próximas reuniões", regexp.MustCompile(`(?:reducao|ajuste|cortes?) de mesma magnitude`)},
+	{"cortes_050_proximas_reunioes", "Cortes de 0,50 p.p. nas próximas reuniões", regexp.MustCompile(`cortes de 0,50 ponto percentual nas proximas reunioes`)},
+	{"magnitude_total_ciclo", "Magnitude total do ciclo", regexp.MustCompile(`magnitude total do ciclo`)},
+	{"ajustes_futuros_ditados", "Ajustes futuros ditados pelo compromisso com a meta", regexp.MustCompile(`ajustes futuros na taxa de juros serao ditados`)},
+	{"passos_futuros_ajustados", "Passos futuros poderão ser ajustados", regexp.MustCompile(`passos futuros da politica monetaria poderao ser ajustados`)},
+	{"sem_indicacao_futura", "Sem indicação futura dos próximos passos", regexp.MustCompile(`sem conferir indicacao futura`)},
+	{"seguira_vigilante", "Comitê seguirá vigilante", regexp.MustCompile(`(?:seguira|se mantera|segue) vigilante|manter a vigilancia`)},
+	{"serenidade_paciencia", "Serenidade e paciência", regexp.MustCompile(`serenidade e paciencia`)},
+	{"serenidade_moderacao", "Serenidade e moderação", regexp.MustCompile(`serenidade e moderacao`)},
+	{"perseveranca_firmeza_serenidade", "Perseverança, firmeza e serenidade", regexp.MustCompile(`perseveranca, firmeza e serenidade`)},
+	{"dependente_de_dados", "Postura dependente de dados", regexp.MustCompile(`dependente de dados`)},
+}
+
+// GuidanceOccurrence é uma frase de uma ata que contém a sinalização Phrase.
+type GuidanceOccurrence struct {
+	Phrase    string `json:"phrase"`
+	Paragraph int    `json:"paragraph"`
+	Sentence  string `json:"sentence"`
+}
+
+// GuidanceMeeting lista as sinalizações encontradas em uma reunião.
+type GuidanceMeeting struct {
+	NumeroReuniao int                  `json:"numero_reuniao"`
+	DataReuniao   string               `json:"data_reuniao"`
+	Phrases       []string             `json:"phrases"`
+	Occurrences   []GuidanceOccurrence `json:"occurrences"`
+}
+
+// GuidanceSpan é um intervalo de reuniões consecutivas em que a sinalização esteve presente.
+type GuidanceSpan struct {
+	FromMeeting int    `json:"from_meeting"`
+	FromDate    string `json:"from_date"`
+	ToMeeting   int    `json:"to_meeting"`
+	ToDate      string `json:"to_date"`
+}
+
+// GuidancePhraseTimeline resume a trajetória de uma sinalização. DisappearedMeeting é a
+// primeira reunião sem a frase após a última ocorrência (ausente se ainda está ativa).
+type GuidancePhraseTimeline struct {
+	ID                 string         `json:"id"`
+	Label              string         `json:"label"`
+	FirstSeenMeeting   int            `json:"first_seen_meeting"`
+	FirstSeenDate      string         `json:"first_seen_date"`
+	LastSeenMeeting    int            `json:"last_seen_meeting"`
+	LastSeenDate       string         `json:"last_seen_date"`
+	DisappearedMeeting int            `json:"disappeared_meeting,omitempty"`
+	DisappearedDate    string         `json:"disappeared_date,omitempty"`
+	Active             bool           `json:"active"`
+	Meetings           []int          `json:"meetings"`
+	Spans              []GuidanceSpan `json:"spans"`
+	LatestSentence     string         `json:"latest_sentence"`
+}
+
+// GuidanceResponse é o corpo de GET /guidance: as sinalizações por reunião (ordem
+// cronológica) e a linha do tempo de cada frase, ordenada pela primeira aparição.
+type GuidanceResponse struct {
+	Meetings []GuidanceMeeting        `json:"meetings"`
+	Phrases  []GuidancePhraseTimeline `json:"phrases"`
+}
+
+// detectGuidance procura as sinalizações nas frases dos parágrafos numerados da ata.
+func detectGuidance(ata CopomAta) GuidanceMeeting {
+	meeting := GuidanceMeeting{
+		NumeroReuniao: ata.NumeroReuniao,
+		DataReuniao:   ata.DataReuniao,
+		Phrases:       []string{},
+		Occurrences:   []GuidanceOccurrence{},
+	}
+	found := make(map[string]bool)
+	for i, paragraph := range numberedParagraphs(ata) {
+		for _, sentence := range splitSentences(paragraph) {
+			folded := foldAccents(normalizeSpace(sentence))
+			for _, phrase := range guidancePhrases {
+				if !phrase.Pattern.MatchString(folded) {
+					continue
+				}
+				meeting.Occurrences = append(meeting.Occurrences, GuidanceOccurrence{
+					Phrase:    phrase.ID,
+					Paragraph: i + 1,
+					Sentence:  normalizeSpace(sentence),
+				})
+				found[phrase.ID] = true
+			}
+		}
+	}
+	// Phrases segue a ordem do catálogo, não a do texto
+	for _, phrase := range guidancePhrases {
+		if found[phrase.ID] {
+			meeting.Phrases = append(meeting.Phrases, phrase.ID)
+		}
+	}
+	return meeting
+}
+
+// buildGuidanceTimeline monta a linha do tempo a partir das reuniões em ordem cronológica.
+// Reuniões sem conteúdo não entram na lista, para não contarem como desaparecimento.
+func buildGuidanceTimeline(meetings []GuidanceMeeting) []GuidancePhraseTimeline {
+	timelines := []GuidancePhraseTimeline{}
+	for _, phrase := range guidancePhrases {
+		t := GuidancePhraseTimeline{ID: phrase.ID, Label: phrase.Label, Meetings: []int{}, Spans: []GuidanceSpan{}}
+		last := -1
+		for i, m := range meetings {
+			if !slices.Contains(m.Phrases, phrase.ID) {
+				continue
+			}
+			if len(t.Meetings) == 0 {
+				t.FirstSeenMeeting, t.FirstSeenDate = m.NumeroReuniao, m.DataReuniao
+			}
+			t.Meetings = append(t.Meetings, m.NumeroReuniao)
+			t.LastSeenMeeting, t.LastSeenDate = m.NumeroReuniao, m.DataReuniao
+			last = i
+			for _, occ := range m.Occurrences {
+				if occ.Phrase == phrase.ID {
+					t.LatestSentence = occ.Sentence
+					break
+				}
+			}
+
+			// Estende o intervalo atual se a reunião anterior também tinha a frase
+			if n := len(t.Spans); n > 0 && i > 0 && t.Spans[n-1].ToMeeting == meetings[i-1].NumeroReuniao {
+				t.Spans[n-1].ToMeeting, t.Spans[n-1].ToDate = m.NumeroReuniao, m.DataReuniao
+			} else {
+				t.Spans = append(t.Spans, GuidanceSpan{
+					FromMeeting: m.NumeroReuniao, FromDate: m.DataReuniao,
+					ToMeeting: m.NumeroReuniao, ToDate: m.DataReuniao,
+				})
+			}
+		}
+		if last < 0 {
+			continue
+		}
+		if last+1 < len(meetings) {
+			t.DisappearedMeeting, t.DisappearedDate = meetings[last+1].NumeroReuniao, meetings[last+1].DataReuniao
+		}
+		t.Active = last == len(meetings)-1
+		timelines = append(timelines, t)
+	}
+	slices.SortStableFunc(timelines, func(a, b GuidancePhraseTimeline) int {
+		return cmp.Compare(a.FirstSeenDate, b.FirstSeenDate)
+	})
+	return timelines
+}
+
+// guidanceIndex guarda as sinalizações detectadas em cada reunião, reconstruído a cada recarga.
+type guidanceIndex struct {
+	mu       sync.RWMutex
+	meetings []GuidanceMeeting // ordem cronológica
+}
+
+func newGuidanceIndex() *guidanceIndex {
+	return &guidanceIndex{}
+}
+
+// rebuild detecta as sinalizações fora do lock e troca o índice de uma só vez.
+func (idx *guidanceIndex) rebuild(atas []CopomAta) {
+	meetings := make([]GuidanceMeeting, 0, len(atas))
+	for _, ata := range atas {
+		if ata.NumeroReuniao == 0 || ata.DataReuniao == "" || ata.Conteudo == "" {
+			continue
+		}
+		meetings = append(meetings, detectGuidance(ata))
+	}
+	slices.SortFunc(meetings, func(a, b GuidanceMeeting) int {
+		return cmp.Or(cmp.Compare(a.DataReuniao, b.DataReuniao), cmp.Compare(a.NumeroReuniao, b.NumeroReuniao))
+	})
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.meetings = meetings
+}
+
+// Timeline restringe as reuniões ao intervalo from/to (YYYY-MM-DD, inclusivos) e monta a
+// linha do tempo sobre elas.
+func (idx *guidanceIndex) Timeline(from, to string) GuidanceResponse {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	meetings := make([]GuidanceMeeting, 0, len(idx.meetings))
+	for _, m := range idx.meetings {
+		if (from == "" || m.DataReuniao >= from) && (to == "" || m.DataReuniao <= to) {
+			meetings = append(meetings, m)
+		}
+	}
+	return GuidanceResponse{Meetings: meetings, Phrases: buildGuidanceTimeline(meetings)}
+}
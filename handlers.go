@@ -1,21 +1,34 @@
 package main
 
 import (
+	"bytes"
+	"cmp"
+	"errors"
 	"fmt"
 	"net/http"
+	"path"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
 )
 
-// PaginatedResponse representa a resposta paginada
-type PaginatedResponse struct {
-	Data       []EnrichedParagraph `json:"data"`
-	Page       int                 `json:"page"`
-	Limit      int                 `json:"limit"`
-	Total      int                 `json:"total"`
-	TotalPages int                 `json:"total_pages"`
+// PaginatedResponse representa a resposta paginada (compartilhada por /atas e /enriched)
+type PaginatedResponse[T any] struct {
+	Data       []T `json:"data"`
+	Page       int `json:"page"`
+	Limit      int `json:"limit"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+	// Links para a página seguinte/anterior via cursor (?cursor=); vazios nas pontas.
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 // ErrorResponse representa uma resposta de erro
@@ -23,29 +36,125 @@ type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// negotiateExportFormat escolhe o formato tabular da resposta: ?format= tem prioridade sobre o
+// header Accept. Retorna "" quando a resposta deve ser o JSON padrão do endpoint.
+func negotiateExportFormat(c *gin.Context) (string, error) {
+	if format := strings.ToLower(c.Query("format")); format != "" {
+		if format == "json" {
+			return "", nil
+		}
+		if !isExportFormat(format) {
+			return "", fmt.Errorf("Parâmetro 'format' inválido: use json, csv, jsonl ou parquet.")
+		}
+		return format, nil
+	}
+
+	accept := c.GetHeader("Accept")
+	switch {
+	case strings.Contains(accept, "text/csv"):
+		return exportFormatCSV, nil
+	case strings.Contains(accept, "application/x-ndjson"), strings.Contains(accept, "application/jsonl"):
+		return exportFormatJSONL, nil
+	case strings.Contains(accept, "application/vnd.apache.parquet"):
+		return exportFormatParquet, nil
+	}
+	return "", nil
+}
+
+// respondTable envia a tabela no formato pedido como anexo para download.
+func respondTable(c *gin.Context, table *exportTable, format string) {
+	var buf bytes.Buffer
+	if err := writeTable(&buf, table, format); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: fmt.Sprintf("Falha ao exportar dados: %v", err)})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", table.Name+"."+format))
+	c.Data(http.StatusOK, exportContentType(format), buf.Bytes())
+}
+
 // ListAtas godoc
-// @Summary Lista todas as atas do COPOM
-// @Description Retorna metadados de todas as atas (sem o conteúdo completo)
+// @Summary Lista as atas do COPOM (paginado)
+// @Description Retorna metadados das atas (sem o conteúdo completo), por padrão da mais recente para a mais antiga.
+// @Description Com ?format=csv|jsonl|parquet (ou header Accept correspondente) retorna todas as atas filtradas (sem paginação) como arquivo tabular.
 // @Tags Atas
-// @Produce json
-// @Success 200 {array} CopomAta
+// @Produce json,text/csv,application/x-ndjson,application/vnd.apache.parquet
+// @Param page query int false "Número da página (ignorado quando cursor é informado)" default(1)
+// @Param limit query int false "Itens por página (máx 100)" default(20)
+// @Param cursor query string false "Cursor opaco retornado em next_cursor/prev_cursor"
+// @Param from query string false "Data mínima da reunião (YYYY-MM-DD)"
+// @Param to query string false "Data máxima da reunião (YYYY-MM-DD)"
+// @Param year query int false "Ano da reunião"
+// @Param has_content query bool false "Apenas atas com (true) ou sem (false) conteúdo"
+// @Param falha_no_parse query bool false "Apenas atas com (true) ou sem (false) falha no parse"
+// @Param sort query string false "Ordenação: campos separados por vírgula, '-' para decrescente" default(-numero_reuniao)
+// @Param format query string false "Formato da resposta" Enums(json, csv, jsonl, parquet)
+// @Param include_content query bool false "Incluir o conteúdo completo nos formatos tabulares" default(false)
+// @Success 200 {object} PaginatedResponse[CopomAta]
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /atas [get]
 func ListAtas(store *ataStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		format, err := negotiateExportFormat(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		filter, err := parseAtaFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		sortSpec := c.DefaultQuery("sort", "-numero_reuniao")
+		sortKeys, err := parseSort(sortSpec, ataSortFields)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		// A URL desempata atas sem número (0) e é o ID do cursor
+		sortKeys = append(sortKeys,
+			sortKey[CopomAta]{compare: ataSortFields["numero_reuniao"]},
+			sortKey[CopomAta]{compare: func(a, b CopomAta) int { return cmp.Compare(a.URL, b.URL) }},
+		)
+
 		store.mu.RLock()
 		defer store.mu.RUnlock()
-		var atasSemConteudo []CopomAta
-		for _, ata := range store.atas {
-			atasSemConteudo = append(atasSemConteudo, CopomAta{
+
+		atas := sortItems(filter.apply(store.atas), sortKeys)
+		if format != "" {
+			includeContent, _ := strconv.ParseBool(c.DefaultQuery("include_content", "false"))
+			respondTable(c, atasTable(atas, includeContent), format)
+			return
+		}
+
+		response, err := buildPage(c, atas, pageQuery[CopomAta]{
+			SortSpec: sortSpec,
+			Keys:     sortKeys,
+			ID:       func(a CopomAta) string { return a.URL },
+			Lookup: func(url string) (CopomAta, bool) {
+				i := slices.IndexFunc(store.atas, func(a CopomAta) bool { return a.URL == url })
+				if i < 0 {
+					return CopomAta{}, false
+				}
+				return store.atas[i], true
+			},
+		})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		for i, ata := range response.Data {
+			response.Data[i] = CopomAta{
 				NumeroReuniao: ata.NumeroReuniao,
 				URL:           ata.URL,
 				Titulo:        ata.Titulo,
 				DataReuniao:   ata.DataReuniao,
 				ValorDolar:    ata.ValorDolar,
 				ValorIPCA:     ata.ValorIPCA,
-			})
+				FalhaNoParse:  ata.FalhaNoParse,
+			}
 		}
-		c.JSON(http.StatusOK, atasSemConteudo)
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -58,6 +167,7 @@ func ListAtas(store *ataStore) gin.HandlerFunc {
 // @Success 200 {object} CopomAta
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /atas/{numero} [get]
 func GetAtaByNumero(store *ataStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -78,21 +188,133 @@ func GetAtaByNumero(store *ataStore) gin.HandlerFunc {
 	}
 }
 
+// GetAtaFacts godoc
+// @Summary Fatos numéricos de uma ata
+// @Description Números extraídos do texto da ata (expectativas de inflação do Focus, projeções do Copom, horizonte relevante,
+// @Description decisão e trajetória da Selic, PIB, câmbio de referência), cada um com o parágrafo e a frase de origem.
+// @Description Períodos são anos ("2025"), trimestres ("2027Q2") ou a data da reunião, para fatos pontuais.
+// @Tags Atas
+// @Produce json
+// @Param numero path int true "Número da reunião"
+// @Param kind query string false "Tipos de fato separados por vírgula" Enums(inflation_expectation, inflation_projection, relevant_horizon_projection, free_prices_projection, administered_prices_projection, selic_decision, selic_path, gdp_growth, exchange_rate_assumption)
+// @Success 200 {object} AtaFactsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /atas/{numero}/facts [get]
+func GetAtaFacts(store *ataStore, index *factIndex) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		numero, err := strconv.Atoi(c.Param("numero"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Número da reunião inválido."})
+			return
+		}
+		kinds, err := parseFactKinds(c.Query("kind"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		store.mu.RLock()
+		ata, found := store.atasPorNumero[numero]
+		store.mu.RUnlock()
+		if !found {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: fmt.Sprintf("Ata número %d não encontrada.", numero)})
+			return
+		}
+
+		c.JSON(http.StatusOK, AtaFactsResponse{
+			NumeroReuniao: ata.NumeroReuniao,
+			DataReuniao:   ata.DataReuniao,
+			Facts:         index.Facts(numero, kinds),
+		})
+	}
+}
+
+// GetAtaDiff godoc
+// @Summary Diff entre duas atas
+// @Description Alinha os parágrafos numerados das duas atas por similaridade e compara as frases dos parágrafos alterados.
+// @Description Por padrão compara com a ata anterior (against=prev).
+// @Tags Atas
+// @Produce json,text/x-diff,text/html
+// @Param numero path int true "Número da reunião"
+// @Param against query string false "prev ou o número da reunião a comparar" default(prev)
+// @Param format query string false "Formato da resposta" Enums(json, unified, html) default(json)
+// @Success 200 {object} AtaDiffResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /atas/{numero}/diff [get]
+func GetAtaDiff(store *ataStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		numero, err := strconv.Atoi(c.Param("numero"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Número da reunião inválido."})
+			return
+		}
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "unified" && format != "html" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Parâmetro 'format' inválido: use json, unified ou html."})
+			return
+		}
+
+		store.mu.RLock()
+		from, to, err := resolveDiffPair(store.atasPorNumero, numero, c.Query("against"))
+		store.mu.RUnlock()
+		if err != nil {
+			status := http.StatusNotFound
+			if errors.Is(err, errDiffAgainstInvalid) {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		diff := diffAtas(from, to)
+		var buf bytes.Buffer
+		switch format {
+		case "unified":
+			diff.WriteUnified(&buf)
+			c.Data(http.StatusOK, "text/x-diff; charset=utf-8", buf.Bytes())
+		case "html":
+			diff.WriteHTML(&buf)
+			c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+		default:
+			c.JSON(http.StatusOK, diff)
+		}
+	}
+}
+
 // ListAtaNumeros godoc
 // @Summary Lista números das reuniões disponíveis
-// @Description Retorna array com os números de todas as reuniões (ordenado decrescente)
+// @Description Retorna array com os números das reuniões (ordenado decrescente), aceitando os mesmos filtros de /atas
 // @Tags Atas
 // @Produce json
+// @Param from query string false "Data mínima da reunião (YYYY-MM-DD)"
+// @Param to query string false "Data máxima da reunião (YYYY-MM-DD)"
+// @Param year query int false "Ano da reunião"
+// @Param has_content query bool false "Apenas atas com (true) ou sem (false) conteúdo"
+// @Param falha_no_parse query bool false "Apenas atas com (true) ou sem (false) falha no parse"
 // @Success 200 {array} int
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /atas/numeros [get]
 func ListAtaNumeros(store *ataStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		filter, err := parseAtaFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
 		store.mu.RLock()
 		defer store.mu.RUnlock()
 
 		numeros := make([]int, 0, len(store.atasPorNumero))
-		for k := range store.atasPorNumero {
-			numeros = append(numeros, k)
+		for k, ata := range store.atasPorNumero {
+			if filter.match(ata) {
+				numeros = append(numeros, k)
+			}
 		}
 
 		sort.Sort(sort.Reverse(sort.IntSlice(numeros)))
@@ -102,31 +324,42 @@ func ListAtaNumeros(store *ataStore) gin.HandlerFunc {
 
 // ListEnriched godoc
 // @Summary Lista parágrafos enriquecidos (paginado)
-// @Description Retorna parágrafos com análise de sentimento do Gemini AI
+// @Description Retorna parágrafos com análise de sentimento do Gemini AI, por padrão ordenados por global_id.
+// @Description Aceita paginação por page/limit ou por cursor (next/prev na resposta), estável entre recargas do dataset.
+// @Description Com ?format=csv|jsonl|parquet (ou header Accept correspondente) retorna todos os parágrafos filtrados (sem paginação) como arquivo tabular.
 // @Tags Enriched
-// @Produce json
-// @Param page query int false "Número da página" default(1)
+// @Produce json,text/csv,application/x-ndjson,application/vnd.apache.parquet
+// @Param page query int false "Número da página (ignorado quando cursor é informado)" default(1)
 // @Param limit query int false "Itens por página (máx 100)" default(20)
+// @Param cursor query string false "Cursor opaco retornado em next_cursor/prev_cursor"
 // @Param meeting query int false "Filtrar por número da reunião"
-// @Success 200 {object} PaginatedResponse
+// @Param meeting_from query int false "Número mínimo da reunião"
+// @Param meeting_to query int false "Número máximo da reunião"
+// @Param from query string false "Data mínima da reunião (YYYY-MM-DD)"
+// @Param to query string false "Data máxima da reunião (YYYY-MM-DD)"
+// @Param dollar_trend query string false "Tendência do dólar (aceita lista separada por vírgula)" Enums(SUBIR, DESCER, NEUTRO)
+// @Param ipca_trend query string false "Tendência do IPCA (aceita lista separada por vírgula)" Enums(SUBIR, DESCER, NEUTRO)
+// @Param dollar_min query number false "Valor mínimo do dólar"
+// @Param dollar_max query number false "Valor máximo do dólar"
+// @Param ipca_min query number false "Valor mínimo do IPCA"
+// @Param ipca_max query number false "Valor máximo do IPCA"
+// @Param contains query string false "Texto contido no parágrafo (ignora acentos e maiúsculas)"
+// @Param topic query string false "Tópico do parágrafo (aceita lista separada por vírgula; basta um coincidir)" Enums(external_scenario, domestic_activity, labor_market, inflation_expectations, fiscal_policy, exchange_rate, balance_of_risks, forward_guidance)
+// @Param sort query string false "Ordenação: campos separados por vírgula, '-' para decrescente (ex: -meeting_date,paragraph_id). Campos: global_id, paragraph_id, meeting_number, meeting_date, url, paragraph, dollar_value, ipca_value, dollar_trend, ipca_trend, reasoning"
+// @Param format query string false "Formato da resposta" Enums(json, csv, jsonl, parquet)
+// @Param include_content query bool false "Incluir o texto do parágrafo nos formatos tabulares" default(false)
+// @Param include_embedding query bool false "Incluir o vetor de embedding de cada parágrafo" default(false)
+// @Success 200 {object} PaginatedResponse[EnrichedParagraph]
 // @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /enriched [get]
-func ListEnriched(enriched *enrichedStore) gin.HandlerFunc {
+func ListEnriched(enriched *enrichedStore, index *similarityIndex) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		enriched.mu.RLock()
 		defer enriched.mu.RUnlock()
 
-		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 		meetingFilter := c.Query("meeting")
 
-		if page < 1 {
-			page = 1
-		}
-		if limit < 1 || limit > 100 {
-			limit = 20
-		}
-
 		var source []EnrichedParagraph
 		if meetingFilter != "" {
 			meetingNum, err := strconv.Atoi(meetingFilter)
@@ -139,32 +372,57 @@ func ListEnriched(enriched *enrichedStore) gin.HandlerFunc {
 			source = enriched.paragraphs
 		}
 
-		total := len(source)
-		start := (page - 1) * limit
-		end := start + limit
-
-		if start >= total {
-			c.JSON(http.StatusOK, PaginatedResponse{
-				Data:       []EnrichedParagraph{},
-				Page:       page,
-				Limit:      limit,
-				Total:      total,
-				TotalPages: (total + limit - 1) / limit,
-			})
+		filter, err := parseEnrichedFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 			return
 		}
+		sortSpec := c.Query("sort")
+		sortKeys, err := parseSort(sortSpec, enrichedSortFields)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		// GlobalID como desempate final: ordem total, independente da ordem de carga.
+		sortKeys = append(sortKeys, sortKey[EnrichedParagraph]{compare: enrichedSortFields["global_id"]})
+		source = sortItems(filter.apply(source), sortKeys)
 
-		if end > total {
-			end = total
+		format, err := negotiateExportFormat(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		includeEmbedding, _ := strconv.ParseBool(c.DefaultQuery("include_embedding", "false"))
+		if format != "" {
+			includeContent, _ := strconv.ParseBool(c.DefaultQuery("include_content", "false"))
+			if includeEmbedding {
+				source = index.withEmbeddings(source)
+			}
+			respondTable(c, enrichedTable(source, includeContent, includeEmbedding), format)
+			return
 		}
 
-		c.JSON(http.StatusOK, PaginatedResponse{
-			Data:       source[start:end],
-			Page:       page,
-			Limit:      limit,
-			Total:      total,
-			TotalPages: (total + limit - 1) / limit,
+		response, err := buildPage(c, source, pageQuery[EnrichedParagraph]{
+			SortSpec: sortSpec,
+			Keys:     sortKeys,
+			ID:       func(p EnrichedParagraph) string { return strconv.Itoa(p.GlobalID) },
+			Lookup: func(id string) (EnrichedParagraph, bool) {
+				globalID, err := strconv.Atoi(id)
+				if err != nil {
+					return EnrichedParagraph{}, false
+				}
+				p, ok := enriched.byGlobalID[globalID]
+				return p, ok
+			},
 		})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if includeEmbedding {
+			response.Data = index.withEmbeddings(response.Data)
+		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -174,11 +432,13 @@ func ListEnriched(enriched *enrichedStore) gin.HandlerFunc {
 // @Tags Enriched
 // @Produce json
 // @Param id path int true "Global ID do parágrafo"
+// @Param include_embedding query bool false "Incluir o vetor de embedding" default(false)
 // @Success 200 {object} EnrichedParagraph
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /enriched/{id} [get]
-func GetEnrichedByID(enriched *enrichedStore) gin.HandlerFunc {
+func GetEnrichedByID(enriched *enrichedStore, index *similarityIndex) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		idStr := c.Param("id")
 		id, err := strconv.Atoi(idStr)
@@ -196,21 +456,75 @@ func GetEnrichedByID(enriched *enrichedStore) gin.HandlerFunc {
 			return
 		}
 
+		if includeEmbedding, _ := strconv.ParseBool(c.DefaultQuery("include_embedding", "false")); includeEmbedding {
+			p = index.withEmbeddings([]EnrichedParagraph{p})[0]
+		}
 		c.JSON(http.StatusOK, p)
 	}
 }
 
+// GetSimilarParagraphs godoc
+// @Summary Parágrafos semanticamente parecidos
+// @Description Retorna os k parágrafos mais próximos (similaridade de cosseno dos embeddings), por padrão de outras reuniões,
+// @Description para acompanhar como um tema evoluiu ao longo das atas.
+// @Tags Enriched
+// @Produce json
+// @Param id path int true "Global ID do parágrafo"
+// @Param k query int false "Quantidade de resultados (máx 50)" default(10)
+// @Param same_meeting query bool false "Incluir parágrafos da mesma reunião" default(false)
+// @Success 200 {object} SimilarResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /enriched/{id}/similar [get]
+func GetSimilarParagraphs(enriched *enrichedStore, index *similarityIndex) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "ID inválido."})
+			return
+		}
+		k, _ := strconv.Atoi(c.DefaultQuery("k", "10"))
+		if k < 1 || k > 50 {
+			k = 10
+		}
+		sameMeeting, err := queryBool(c, "same_meeting")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		enriched.mu.RLock()
+		source, found := enriched.byGlobalID[id]
+		enriched.mu.RUnlock()
+		if !found {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: fmt.Sprintf("Parágrafo com global_id %d não encontrado.", id)})
+			return
+		}
+
+		response, ok := index.Similar(source, k, sameMeeting != nil && *sameMeeting)
+		if !ok {
+			c.JSON(http.StatusConflict, ErrorResponse{Error: fmt.Sprintf("Parágrafo %d ainda não tem embedding. Execute -mode=embed.", id)})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
 // GetEnrichedByMeeting godoc
 // @Summary Lista parágrafos de uma reunião específica
 // @Description Retorna todos os parágrafos enriquecidos de uma reunião do COPOM
 // @Tags Enriched
 // @Produce json
 // @Param numero path int true "Número da reunião"
+// @Param include_embedding query bool false "Incluir o vetor de embedding de cada parágrafo" default(false)
 // @Success 200 {array} EnrichedParagraph
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
 // @Router /enriched/meeting/{numero} [get]
-func GetEnrichedByMeeting(enriched *enrichedStore) gin.HandlerFunc {
+func GetEnrichedByMeeting(enriched *enrichedStore, index *similarityIndex) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		numStr := c.Param("numero")
 		num, err := strconv.Atoi(numStr)
@@ -228,6 +542,510 @@ func GetEnrichedByMeeting(enriched *enrichedStore) gin.HandlerFunc {
 			return
 		}
 
+		if includeEmbedding, _ := strconv.ParseBool(c.DefaultQuery("include_embedding", "false")); includeEmbedding {
+			paragraphs = index.withEmbeddings(paragraphs)
+		}
 		c.JSON(http.StatusOK, paragraphs)
 	}
 }
+
+// SearchResponse representa o resultado da busca textual
+type SearchResponse struct {
+	Query string      `json:"query"`
+	Total int         `json:"total"`
+	Hits  []SearchHit `json:"hits"`
+}
+
+// SearchDocuments godoc
+// @Summary Busca textual nas atas e parágrafos
+// @Description Busca com normalização de acentos e stemming em português (ex: "desancoragem" encontra "desancoradas").
+// @Description Resultados ordenados por relevância (BM25), com trecho destacado com <mark>.
+// @Tags Busca
+// @Produce json
+// @Param q query string true "Termos da busca"
+// @Param kind query string false "Restringir a atas ou parágrafos" Enums(ata, paragraph)
+// @Param limit query int false "Máximo de resultados (máx 100)" default(20)
+// @Success 200 {object} SearchResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /search [get]
+func SearchDocuments(index *searchIndex) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := strings.TrimSpace(c.Query("q"))
+		if query == "" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Parâmetro 'q' é obrigatório."})
+			return
+		}
+		kind := c.Query("kind")
+		if kind != "" && kind != searchKindAta && kind != searchKindParagraph {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Parâmetro 'kind' inválido: use ata ou paragraph."})
+			return
+		}
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+		if limit < 1 || limit > 100 {
+			limit = 20
+		}
+
+		total, hits := index.Search(query, kind, limit)
+		c.JSON(http.StatusOK, SearchResponse{Query: query, Total: total, Hits: hits})
+	}
+}
+
+// GetTimeSeries godoc
+// @Summary Séries temporais por reunião
+// @Description Retorna arrays alinhados por data da reunião (ordem cronológica) com dólar, IPCA e sentimento agregado.
+// @Description O sentimento de cada reunião é (SUBIR − DESCER) / n sobre os parágrafos enriquecidos, de -1 a 1. Valores ausentes vêm como null.
+// @Description Com ?topic= o sentimento é calculado por tópico (ex: só forward_guidance).
+// @Tags Séries
+// @Produce json
+// @Param series query string false "Séries separadas por vírgula (padrão: todas)" default(dollar,ipca,dollar_sentiment,ipca_sentiment)
+// @Param from query string false "Data mínima da reunião (YYYY-MM-DD)"
+// @Param to query string false "Data máxima da reunião (YYYY-MM-DD)"
+// @Param topic query string false "Considera no sentimento apenas parágrafos destes tópicos (lista separada por vírgula)" Enums(external_scenario, domestic_activity, labor_market, inflation_expectations, fiscal_policy, exchange_rate, balance_of_risks, forward_guidance)
+// @Success 200 {object} TimeSeriesResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /timeseries [get]
+func GetTimeSeries(store *ataStore, enriched *enrichedStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		series, err := parseSeries(c.Query("series"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		from, err := queryDate(c, "from")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		to, err := queryDate(c, "to")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		topics, err := queryTopics(c, "topic")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		paragraphs := enriched.all()
+		if topics != nil {
+			paragraphs = enrichedFilter{Topics: topics}.apply(paragraphs)
+		}
+		c.JSON(http.StatusOK, buildTimeSeries(store.all(), paragraphs, series, from, to))
+	}
+}
+
+// GetGuidance godoc
+// @Summary Linha do tempo do forward guidance
+// @Description Detecta nas atas as frases de sinalização do Copom (ex: "interrupção do ciclo", "período bastante prolongado",
+// @Description "não hesitará em retomar"), normalizadas para identificadores estáveis, e informa quando cada uma apareceu e desapareceu.
+// @Description Reuniões sem conteúdo são ignoradas.
+// @Tags Séries
+// @Produce json
+// @Param from query string false "Data mínima da reunião (YYYY-MM-DD)"
+// @Param to query string false "Data máxima da reunião (YYYY-MM-DD)"
+// @Success 200 {object} GuidanceResponse
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /guidance [get]
+func GetGuidance(index *guidanceIndex) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from, err := queryDate(c, "from")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		to, err := queryDate(c, "to")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, index.Timeline(from, to))
+	}
+}
+
+// GetStats godoc
+// @Summary Estatísticas dos datasets
+// @Description Contagem de atas por ano, estatísticas de dólar/IPCA/tamanho do conteúdo, completude dos campos,
+// @Description taxa de falhas no parse e distribuição dos rótulos de tendência dos parágrafos enriquecidos.
+// @Tags Séries
+// @Produce json
+// @Success 200 {object} StatsResponse
+// @Security ApiKeyAuth
+// @Router /stats [get]
+func GetStats(store *ataStore, enriched *enrichedStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, computeStats(store.all(), enriched.all()))
+	}
+}
+
+// ListCharts godoc
+// @Summary Lista os gráficos disponíveis
+// @Tags Gráficos
+// @Produce json
+// @Success 200 {array} ChartInfo
+// @Security ApiKeyAuth
+// @Router /charts [get]
+func ListCharts() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, chartList())
+	}
+}
+
+// GetChart godoc
+// @Summary Renderiza um gráfico do dataset em SVG
+// @Description Gera sob demanda os gráficos do antigo graficos_dataset.py (ex: /charts/evolucao-dolar.svg).
+// @Description Apenas SVG é suportado; pedidos .png retornam 415.
+// @Tags Gráficos
+// @Produce image/svg+xml
+// @Param name path string true "Nome do gráfico com extensão .svg" Enums(evolucao-dolar.svg, evolucao-ipca.svg, atas-por-ano.svg, correlacao-dolar-ipca.svg, tamanho-atas.svg, boxplot-ipca-decada.svg, dolar-ipca-dual.svg, completude.svg)
+// @Param from query string false "Data mínima da reunião (YYYY-MM-DD)"
+// @Param to query string false "Data máxima da reunião (YYYY-MM-DD)"
+// @Success 200 {string} string "Documento SVG"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /charts/{name} [get]
+func GetChart(store *ataStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		ext := path.Ext(name)
+		switch ext {
+		case ".svg", "":
+		case ".png":
+			c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{Error: "Renderização em PNG não suportada: use a extensão .svg."})
+			return
+		default:
+			c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{Error: fmt.Sprintf("Formato '%s' não suportado: use a extensão .svg.", ext)})
+			return
+		}
+		spec, ok := findChart(strings.TrimSuffix(name, ext))
+		if !ok {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: fmt.Sprintf("Gráfico não encontrado. Disponíveis: %s.", strings.Join(chartNames(), ", "))})
+			return
+		}
+		from, err := queryDate(c, "from")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		to, err := queryDate(c, "to")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		svg := renderChart(spec, buildChartData(store.all(), from, to))
+		c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", []byte(svg))
+	}
+}
+
+// ReloadDatasets godoc
+// @Summary Recarrega os datasets do disco
+// @Description Relê dataset_raw.json e dataset_enriched.json e troca os dados servidos sem reiniciar o servidor.
+// @Description Se um dos arquivos não puder ser lido, os dados atuais dele são mantidos e o erro é retornado.
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} ReloadResult
+// @Failure 500 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/reload [post]
+func ReloadDatasets(reloader *datasetReloader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := reloader.Reload()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: fmt.Sprintf("Falha ao recarregar datasets: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// StartJob godoc
+// @Summary Dispara um job de scrape ou enrich
+// @Description Executa o scraping (scrape) ou o enriquecimento via Gemini (enrich) em segundo plano e retorna o job criado.
+// @Description Só um job roda por vez. Ao terminar, os datasets são recarregados nos dados servidos.
+// @Tags Admin
+// @Produce json
+// @Success 202 {object} Job
+// @Failure 409 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/jobs/scrape [post]
+// @Router /admin/jobs/enrich [post]
+func StartJob(jobs *jobRegistry, kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		job, err := jobs.Start(kind)
+		if errors.Is(err, errJobAlreadyRunning) {
+			c.JSON(http.StatusConflict, ErrorResponse{Error: fmt.Sprintf("Já existe um job em execução (%s %s).", job.Kind, job.ID)})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.Header("Location", "/admin/jobs/"+job.ID)
+		c.JSON(http.StatusAccepted, job)
+	}
+}
+
+// ListJobs godoc
+// @Summary Lista os jobs
+// @Description Jobs do mais recente para o mais antigo, sem os logs (os terminados mais antigos são descartados).
+// @Tags Admin
+// @Produce json
+// @Success 200 {array} Job
+// @Security ApiKeyAuth
+// @Router /admin/jobs [get]
+func ListJobs(jobs *jobRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, jobs.List())
+	}
+}
+
+// GetJob godoc
+// @Summary Status de um job
+// @Description Retorna status, progresso, erro e as últimas linhas de log do job.
+// @Tags Admin
+// @Produce json
+// @Param id path string true "ID do job"
+// @Success 200 {object} Job
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/jobs/{id} [get]
+func GetJob(jobs *jobRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		job, err := jobs.Get(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: fmt.Sprintf("Job %s não encontrado.", c.Param("id"))})
+			return
+		}
+		c.JSON(http.StatusOK, job)
+	}
+}
+
+// CancelJob godoc
+// @Summary Cancela um job
+// @Description Pede o cancelamento do job; ele para no próximo ponto de verificação (entre atas no scrape, entre parágrafos no enrich)
+// @Description e o trabalho já feito fica salvo. Acompanhe o status em GET /admin/jobs/{id}.
+// @Tags Admin
+// @Produce json
+// @Param id path string true "ID do job"
+// @Success 202 {object} Job
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/jobs/{id}/cancel [post]
+func CancelJob(jobs *jobRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		job, err := jobs.Cancel(id)
+		switch {
+		case errors.Is(err, errJobNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: fmt.Sprintf("Job %s não encontrado.", id)})
+		case errors.Is(err, errJobFinished):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: fmt.Sprintf("Job %s já terminou (%s).", id, job.Status)})
+		default:
+			c.JSON(http.StatusAccepted, job)
+		}
+	}
+}
+
+// GetSchedule godoc
+// @Summary Estado do agendador
+// @Description Próxima reunião aguardada, publicação esperada da ata e próxima tentativa de scrape. Disponível apenas no modo 'daemon'.
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} ScheduleStatus
+// @Security ApiKeyAuth
+// @Router /admin/schedule [get]
+func GetSchedule(s *scheduler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.Status())
+	}
+}
+
+// ListWebhooks godoc
+// @Summary Lista os webhooks configurados
+// @Description Webhooks lidos de webhooks.json (ou WEBHOOKS_CONFIG) e os eventos que cada um recebe. Os secrets não são expostos.
+// @Tags Admin
+// @Produce json
+// @Success 200 {array} WebhookInfo
+// @Security ApiKeyAuth
+// @Router /admin/webhooks [get]
+func ListWebhooks(webhooks *webhookDispatcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, webhooks.Webhooks())
+	}
+}
+
+// ListWebhookDeliveries godoc
+// @Summary Log de entregas dos webhooks
+// @Description Entregas da mais recente para a mais antiga (apenas as últimas 200, em memória), com tentativas e o resultado da última.
+// @Tags Admin
+// @Produce json
+// @Param webhook query string false "ID do webhook"
+// @Param status query string false "Status da entrega" Enums(pending, succeeded, failed)
+// @Success 200 {array} WebhookDelivery
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /admin/webhooks/deliveries [get]
+func ListWebhookDeliveries(webhooks *webhookDispatcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := c.Query("status")
+		if status != "" && status != DeliveryPending && status != DeliverySucceeded && status != DeliveryFailed {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("Status '%s' inválido (use pending, succeeded ou failed).", status)})
+			return
+		}
+		c.JSON(http.StatusOK, webhooks.Deliveries(c.Query("webhook"), status))
+	}
+}
+
+// StreamEvents godoc
+// @Summary Eventos do pipeline (SSE)
+// @Description Stream Server-Sent Events com os eventos do servidor: ata.scraped, enrichment.completed, paragraph.enriched,
+// @Description dataset.reloaded, job.started, job.progress e job.finished. Cada evento tem id sequencial e o JSON do Event em data.
+// @Description Ao reconectar com o cabeçalho Last-Event-ID (ou ?last_event_id=) os eventos perdidos são reenviados a partir do buffer
+// @Description em memória; se parte deles já foi descartada, um evento stream.gap (sem id) avisa o cliente.
+// @Tags Eventos
+// @Produce text/event-stream
+// @Param types query string false "Tipos de evento separados por vírgula (padrão: todos)"
+// @Param last_event_id query int false "Alternativa ao cabeçalho Last-Event-ID"
+// @Param Last-Event-ID header int false "Último evento recebido"
+// @Success 200 {object} Event
+// @Failure 400 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /events [get]
+func StreamEvents(stream *eventStream) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var types map[string]bool
+		if val := c.Query("types"); val != "" {
+			types = make(map[string]bool)
+			for _, t := range strings.Split(val, ",") {
+				t = strings.TrimSpace(t)
+				if !slices.Contains(allEventTypes, t) {
+					c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("Parâmetro 'types' inválido: use %s.", strings.Join(allEventTypes, ", "))})
+					return
+				}
+				types[t] = true
+			}
+		}
+
+		lastIDStr := c.GetHeader("Last-Event-ID")
+		if lastIDStr == "" {
+			lastIDStr = c.Query("last_event_id")
+		}
+		var lastID int64
+		if lastIDStr != "" {
+			id, err := strconv.ParseInt(lastIDStr, 10, 64)
+			if err != nil || id < 0 {
+				c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Last-Event-ID inválido."})
+				return
+			}
+			lastID = id
+		}
+
+		replay, ch, gap := stream.Subscribe(lastID)
+		defer stream.Unsubscribe(ch)
+
+		// Definido antes do primeiro Flush: sem replay, nenhum sse.Event foi renderizado ainda
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no") // Desativa o buffer de proxies como o nginx
+		c.Status(http.StatusOK)
+
+		send := func(ev Event) {
+			if types == nil || types[ev.Type] {
+				c.Render(-1, sse.Event{Id: strconv.FormatInt(ev.ID, 10), Event: ev.Type, Data: ev})
+			}
+		}
+		if gap != nil {
+			c.Render(-1, sse.Event{Event: "stream.gap", Data: gap})
+		}
+		for _, ev := range replay {
+			send(ev)
+		}
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(eventsHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case ev, ok := <-ch:
+				if !ok {
+					// Cliente lento desconectado: ele reconecta com Last-Event-ID
+					return
+				}
+				send(ev)
+			case <-heartbeat.C:
+				// Comentário SSE, mantém a conexão aberta através de proxies
+				c.Writer.WriteString(": ping\n\n")
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// feedSelfURL reconstrói a URL absoluta do feed, respeitando proxies (X-Forwarded-Proto).
+func feedSelfURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host + c.Request.URL.Path
+}
+
+// serveFeed monta as entradas e responde com o formato gerado por render.
+func serveFeed(c *gin.Context, store *ataStore, facts *factIndex, contentType string, render func([]feedEntry, string) ([]byte, error)) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultFeedLimit)))
+	if limit < 1 || limit > maxFeedLimit {
+		limit = defaultFeedLimit
+	}
+	body, err := render(buildFeedEntries(store.all(), facts, limit), feedSelfURL(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: fmt.Sprintf("Falha ao gerar o feed: %v", err)})
+		return
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// GetAtomFeed godoc
+// @Summary Feed Atom das atas
+// @Description Uma entrada por reunião, da mais recente para a mais antiga: título, data, link para a ata no site do BCB,
+// @Description resumo da decisão sobre a Selic (comparada com a reunião anterior) e os primeiros parágrafos da ata.
+// @Tags Feeds
+// @Produce application/atom+xml
+// @Param limit query int false "Número de entradas (máx 100)" default(20)
+// @Success 200 {string} string "Feed Atom"
+// @Security ApiKeyAuth
+// @Router /feed.atom [get]
+func GetAtomFeed(store *ataStore, facts *factIndex) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serveFeed(c, store, facts, "application/atom+xml; charset=utf-8", renderAtom)
+	}
+}
+
+// GetRSSFeed godoc
+// @Summary Feed RSS das atas
+// @Description Mesmo conteúdo de /feed.atom em RSS 2.0; a descrição de cada item traz a decisão e os primeiros parágrafos em HTML.
+// @Tags Feeds
+// @Produce application/rss+xml
+// @Param limit query int false "Número de entradas (máx 100)" default(20)
+// @Success 200 {string} string "Feed RSS"
+// @Security ApiKeyAuth
+// @Router /feed.rss [get]
+func GetRSSFeed(store *ataStore, facts *factIndex) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serveFeed(c, store, facts, "application/rss+xml; charset=utf-8", renderRSS)
+	}
+}
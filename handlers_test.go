@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newEventsTestServer(t *testing.T) (*eventBus, *httptest.Server) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	events := newEventBus()
+	stream := newEventStream(10)
+	events.Subscribe(stream.Handle)
+	router := gin.New()
+	router.GET("/events", StreamEvents(stream))
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return events, srv
+}
+
+// Conexão nova (sem replay): o EventSource dos navegadores exige o Content-Type já nos cabeçalhos.
+func TestStreamEventsContentTypeOnFreshConnection(t *testing.T) {
+	_, srv := newEventsTestServer(t)
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Get(srv.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, esperado 200", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("Content-Type = %q, esperado text/event-stream", ct)
+	}
+}
+
+func TestStreamEventsReplaysAfterLastEventID(t *testing.T) {
+	events, srv := newEventsTestServer(t)
+	for i := 0; i < 3; i++ {
+		events.Publish(EventDatasetReloaded, ReloadResult{Atas: i})
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/events", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(resp.Body)
+	for len(ids) < 2 && scanner.Scan() {
+		if id, ok := strings.CutPrefix(scanner.Text(), "id:"); ok {
+			ids = append(ids, id)
+		}
+	}
+	if strings.Join(ids, ",") != "2,3" {
+		t.Fatalf("ids reenviados = %v, esperado [2 3]", ids)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// paragraphKey identifica um parágrafo independentemente de GlobalID/ParagraphID, que divergem
+// entre máquinas: número da reunião + hash do texto normalizado.
+type paragraphKey struct {
+	Meeting  int
+	TextHash string
+}
+
+// paragraphTextHash ignora diferenças de espaços em branco, que variam conforme a extração do HTML.
+func paragraphTextHash(text string) string {
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(text), " ")))
+	return hex.EncodeToString(sum[:])
+}
+
+func keyOf(p EnrichedParagraph) paragraphKey {
+	return paragraphKey{Meeting: p.MeetingNumber, TextHash: paragraphTextHash(p.Paragraph)}
+}
+
+// importConflict registra um parágrafo presente nos dois datasets com predições diferentes.
+// A versão local é mantida.
+type importConflict struct {
+	MeetingNumber int
+	ParagraphID   int
+	Local         GeminiPrediction
+	Incoming      GeminiPrediction
+}
+
+// importIDConflict registra um parágrafo importado cujo ParagraphID já é usado localmente
+// por outro texto da mesma reunião (atas extraídas de forma diferente). O parágrafo não é
+// importado: o ParagraphID é o índice de splitParagraphs que o enricher usa para pular
+// trabalho, e inventar outro o desassociaria do texto.
+type importIDConflict struct {
+	MeetingNumber int
+	ParagraphID   int
+	Text          string
+}
+
+type importResult struct {
+	Enriched          []EnrichedParagraph
+	Atas              []CopomAta
+	ParagraphsAdded   int
+	ParagraphsSkipped int
+	// LocalDuplicates são parágrafos locais com reunião + texto repetidos; todos são mantidos.
+	LocalDuplicates int
+	AtasAdded       int
+	AtasReplaced    int
+	Conflicts       []importConflict
+	IDConflicts     []importIDConflict
+}
+
+// mergeEnrichedDatasets une os parágrafos de incoming ao dataset local, deduplicando por
+// reunião + hash do texto. Os parágrafos locais são mantidos como estão (inclusive GlobalID);
+// os importados recebem GlobalIDs a partir do maior ID local.
+func mergeEnrichedDatasets(result *importResult, local, incoming []EnrichedParagraph) {
+	index := make(map[paragraphKey]int, len(local)+len(incoming))
+	usedParagraphIDs := make(map[int]map[int]bool)
+
+	merged := make([]EnrichedParagraph, 0, len(local)+len(incoming))
+	add := func(p EnrichedParagraph) {
+		if _, dup := index[keyOf(p)]; !dup {
+			index[keyOf(p)] = len(merged)
+		}
+		merged = append(merged, p)
+		if usedParagraphIDs[p.MeetingNumber] == nil {
+			usedParagraphIDs[p.MeetingNumber] = make(map[int]bool)
+		}
+		usedParagraphIDs[p.MeetingNumber][p.ParagraphID] = true
+	}
+
+	for _, p := range local {
+		if _, dup := index[keyOf(p)]; dup {
+			result.LocalDuplicates++
+		}
+		add(p)
+	}
+
+	var added []EnrichedParagraph
+	for _, p := range incoming {
+		if i, dup := index[keyOf(p)]; dup {
+			result.ParagraphsSkipped++
+			existing := merged[i]
+			if existing.Prediction.DollarTrend != p.Prediction.DollarTrend || existing.Prediction.IPCATrend != p.Prediction.IPCATrend {
+				result.Conflicts = append(result.Conflicts, importConflict{
+					MeetingNumber: existing.MeetingNumber,
+					ParagraphID:   existing.ParagraphID,
+					Local:         existing.Prediction,
+					Incoming:      p.Prediction,
+				})
+			}
+			continue
+		}
+		if usedParagraphIDs[p.MeetingNumber][p.ParagraphID] {
+			result.IDConflicts = append(result.IDConflicts, importIDConflict{
+				MeetingNumber: p.MeetingNumber,
+				ParagraphID:   p.ParagraphID,
+				Text:          p.Paragraph,
+			})
+			continue
+		}
+		add(p)
+		added = append(added, p)
+		result.ParagraphsAdded++
+	}
+
+	// Os importados são o final de merged: troca pela versão numerada (e ordenada)
+	merged = append(merged[:len(merged)-len(added)], assignNewGlobalIDs(local, added)...)
+	result.Enriched = merged
+}
+
+// assignNewGlobalIDs numera os parágrafos importados a partir do maior GlobalID local, em ordem
+// de reunião, ParagraphID e hash do texto (independe da ordem do arquivo importado). IDs locais
+// nunca mudam, para não quebrar links /enriched/:id, cursores e referências do journal.
+func assignNewGlobalIDs(local, added []EnrichedParagraph) []EnrichedParagraph {
+	next := 1
+	for _, p := range local {
+		if p.GlobalID >= next {
+			next = p.GlobalID + 1
+		}
+	}
+	sort.SliceStable(added, func(i, j int) bool {
+		a, b := added[i], added[j]
+		if a.MeetingNumber != b.MeetingNumber {
+			return a.MeetingNumber < b.MeetingNumber
+		}
+		if a.ParagraphID != b.ParagraphID {
+			return a.ParagraphID < b.ParagraphID
+		}
+		return paragraphTextHash(a.Paragraph) < paragraphTextHash(b.Paragraph)
+	})
+	for i := range added {
+		added[i].GlobalID = next
+		next++
+	}
+	return added
+}
+
+// mergeAtas adiciona as atas ausentes localmente e substitui atas locais que falharam no parse
+// quando a outra máquina conseguiu extrair o conteúdo.
+func mergeAtas(result *importResult, local, incoming []CopomAta) {
+	merged := append([]CopomAta{}, local...)
+	byNumber := make(map[int]int, len(merged))
+	for i, ata := range merged {
+		if ata.NumeroReuniao != 0 {
+			byNumber[ata.NumeroReuniao] = i
+		}
+	}
+
+	for _, ata := range incoming {
+		if ata.NumeroReuniao == 0 {
+			continue
+		}
+		i, exists := byNumber[ata.NumeroReuniao]
+		switch {
+		case !exists:
+			byNumber[ata.NumeroReuniao] = len(merged)
+			merged = append(merged, ata)
+			result.AtasAdded++
+		case merged[i].FalhaNoParse && !ata.FalhaNoParse:
+			merged[i] = ata
+			result.AtasReplaced++
+		}
+	}
+	result.Atas = merged
+}
+
+// Print escreve o resumo da importação e a lista de conflitos de predição.
+func (r *importResult) Print(w io.Writer) {
+	line := strings.Repeat("=", 80)
+	fmt.Fprintln(w, line)
+	fmt.Fprintln(w, "RELATÓRIO DE IMPORTAÇÃO")
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "  Atas adicionadas: %d\n", r.AtasAdded)
+	fmt.Fprintf(w, "  Atas substituídas (falha no parse local): %d\n", r.AtasReplaced)
+	fmt.Fprintf(w, "  Parágrafos adicionados: %d\n", r.ParagraphsAdded)
+	fmt.Fprintf(w, "  Parágrafos já existentes (ignorados): %d\n", r.ParagraphsSkipped)
+	fmt.Fprintf(w, "  Parágrafos locais duplicados (mantidos): %d\n", r.LocalDuplicates)
+	fmt.Fprintf(w, "  Total de parágrafos após a importação: %d\n", len(r.Enriched))
+	fmt.Fprintf(w, "  Conflitos de predição (mantida a versão local): %d\n", len(r.Conflicts))
+	for _, c := range r.Conflicts {
+		fmt.Fprintf(w, "    - reunião %d, parágrafo %d: local dólar=%s ipca=%s | importado dólar=%s ipca=%s\n",
+			c.MeetingNumber, c.ParagraphID, c.Local.DollarTrend, c.Local.IPCATrend, c.Incoming.DollarTrend, c.Incoming.IPCATrend)
+	}
+	fmt.Fprintf(w, "  Conflitos de paragraph_id (outro texto local, não importados): %d\n", len(r.IDConflicts))
+	for _, c := range r.IDConflicts {
+		fmt.Fprintf(w, "    - reunião %d, parágrafo %d: %s\n", c.MeetingNumber, c.ParagraphID, truncateRunes(c.Text, 80))
+	}
+	fmt.Fprintln(w, line)
+}
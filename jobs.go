@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Jobs em segundo plano do servidor (POST /admin/jobs/scrape e /admin/jobs/enrich).
+//
+// Cada job roda scrapeDataset/enrichDataset em uma goroutine com contexto cancelável e um
+// logger próprio, que escreve no log do servidor e no buffer de logs do job. Ao terminar
+// (inclusive cancelado ou com erro, já que o trabalho parcial fica salvo) os datasets são
+// recarregados nos stores.
+
+// Tipos de job
+const (
+	JobScrape = "scrape"
+	JobEnrich = "enrich"
+)
+
+// Estados de um job
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCanceled  = "canceled"
+)
+
+const (
+	// maxJobLogLines limita as linhas de log guardadas por job (as mais antigas são descartadas).
+	maxJobLogLines = 1000
+	// maxFinishedJobs limita quantos jobs terminados ficam no registro.
+	maxFinishedJobs = 50
+)
+
+var (
+	errJobNotFound       = errors.New("job não encontrado")
+	errJobAlreadyRunning = errors.New("já existe um job em execução")
+	errJobFinished       = errors.New("job já terminou")
+)
+
+// progressFunc recebe o progresso (done de total) de scrape/enrich. Pode ser nil (CLI).
+type progressFunc func(done, total int)
+
+func (p progressFunc) report(done, total int) {
+	if p != nil {
+		p(done, total)
+	}
+}
+
+// sleepContext espera d ou até ctx ser cancelado.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// JobProgress é o avanço de um job: atas processadas (enrich) ou links visitados (scrape).
+type JobProgress struct {
+	Done    int     `json:"done"`
+	Total   int     `json:"total"`
+	Percent float64 `json:"percent"`
+}
+
+// Job é o estado de um job retornado pela API.
+type Job struct {
+	ID         string      `json:"id"`
+	Kind       string      `json:"kind"`
+	Status     string      `json:"status"`
+	CreatedAt  time.Time   `json:"created_at"`
+	StartedAt  *time.Time  `json:"started_at,omitempty"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+	Progress   JobProgress `json:"progress"`
+	Error      string      `json:"error,omitempty"`
+	Logs       []string    `json:"logs,omitempty"`
+}
+
+// jobRunner executa o trabalho de um tipo de job.
+type jobRunner func(ctx context.Context, logger *log.Logger, progress progressFunc) error
+
+type jobEntry struct {
+	job    Job
+	cancel context.CancelFunc
+	done   chan struct{} // fechado quando o job termina
+}
+
+// jobRegistry guarda os jobs do servidor. Só um job roda por vez: scrape e enrich gravam os
+// mesmos arquivos de dataset (e o enrich usa o journal).
+type jobRegistry struct {
+	mu       sync.Mutex
+	jobs     map[string]*jobEntry
+	order    []string // IDs em ordem de criação
+	runners  map[string]jobRunner
+	events   *eventBus // recebe job.started, job.progress e job.finished
+	onFinish func(Job)
+}
+
+func newJobRegistry(runners map[string]jobRunner, events *eventBus, onFinish func(Job)) *jobRegistry {
+	return &jobRegistry{
+		jobs:     make(map[string]*jobEntry),
+		runners:  runners,
+		events:   events,
+		onFinish: onFinish,
+	}
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// Start cria e dispara um job do tipo kind.
+func (r *jobRegistry) Start(kind string) (Job, error) {
+	runner, ok := r.runners[kind]
+	if !ok {
+		return Job{}, fmt.Errorf("tipo de job desconhecido: %s", kind)
+	}
+
+	r.mu.Lock()
+	for _, e := range r.jobs {
+		if e.job.Status == JobQueued || e.job.Status == JobRunning {
+			r.mu.Unlock()
+			return e.job, errJobAlreadyRunning
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	entry := &jobEntry{
+		job:    Job{ID: newJobID(), Kind: kind, Status: JobQueued, CreatedAt: time.Now()},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	r.jobs[entry.job.ID] = entry
+	r.order = append(r.order, entry.job.ID)
+	r.pruneLocked()
+	job := entry.job
+	r.mu.Unlock()
+
+	go r.run(ctx, entry, runner)
+	return job, nil
+}
+
+func (r *jobRegistry) run(ctx context.Context, entry *jobEntry, runner jobRunner) {
+	id := entry.job.ID
+	var started Job
+	r.update(id, func(j *Job) {
+		now := time.Now()
+		j.Status = JobRunning
+		j.StartedAt = &now
+		started = *j
+	})
+	r.publish(EventJobStarted, started)
+
+	logger := log.New(io.MultiWriter(log.Writer(), &jobLogWriter{registry: r, id: id}),
+		fmt.Sprintf("[job %s %s] ", entry.job.Kind, id), log.LstdFlags)
+	progress := func(done, total int) {
+		var current Job
+		r.update(id, func(j *Job) {
+			j.Progress = JobProgress{Done: done, Total: total}
+			if total > 0 {
+				j.Progress.Percent = float64(done) / float64(total) * 100
+			}
+			current = *j
+		})
+		r.publish(EventJobProgress, current)
+	}
+
+	err := runner(ctx, logger, progress)
+
+	var finished Job
+	r.update(id, func(j *Job) {
+		now := time.Now()
+		j.FinishedAt = &now
+		switch {
+		case ctx.Err() != nil:
+			j.Status = JobCanceled
+		case err != nil:
+			j.Status = JobFailed
+			j.Error = err.Error()
+		default:
+			j.Status = JobSucceeded
+		}
+		finished = *j
+	})
+	entry.cancel()
+	logger.Printf("Job finalizado: %s", finished.Status)
+	if r.onFinish != nil {
+		r.onFinish(finished)
+	}
+	r.publish(EventJobFinished, finished)
+	close(entry.done)
+}
+
+// publish envia o job (sem os logs) ao eventBus, fora do lock do registro.
+func (r *jobRegistry) publish(eventType string, job Job) {
+	job.Logs = nil
+	r.events.Publish(eventType, job)
+}
+
+func (r *jobRegistry) update(id string, fn func(j *Job)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.jobs[id]; ok {
+		fn(&e.job)
+	}
+}
+
+// pruneLocked descarta os jobs terminados mais antigos além de maxFinishedJobs.
+func (r *jobRegistry) pruneLocked() {
+	finished := 0
+	for i := len(r.order) - 1; i >= 0; i-- {
+		id := r.order[i]
+		status := r.jobs[id].job.Status
+		if status == JobQueued || status == JobRunning {
+			continue
+		}
+		finished++
+		if finished > maxFinishedJobs {
+			delete(r.jobs, id)
+			r.order = slices.Delete(r.order, i, i+1)
+		}
+	}
+}
+
+// Get retorna uma cópia do job (com os logs).
+func (r *jobRegistry) Get(id string) (Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.jobs[id]
+	if !ok {
+		return Job{}, errJobNotFound
+	}
+	job := e.job
+	job.Logs = slices.Clone(e.job.Logs)
+	return job, nil
+}
+
+// Wait espera o job terminar (inclusive o onFinish) ou ctx ser cancelado.
+func (r *jobRegistry) Wait(ctx context.Context, id string) (Job, error) {
+	r.mu.Lock()
+	e, ok := r.jobs[id]
+	r.mu.Unlock()
+	if !ok {
+		return Job{}, errJobNotFound
+	}
+	select {
+	case <-ctx.Done():
+		return Job{}, ctx.Err()
+	case <-e.done:
+		return r.Get(id)
+	}
+}
+
+// List retorna os jobs do mais recente para o mais antigo, sem os logs.
+func (r *jobRegistry) List() []Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	jobs := make([]Job, 0, len(r.order))
+	for i := len(r.order) - 1; i >= 0; i-- {
+		job := r.jobs[r.order[i]].job
+		job.Logs = nil
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// Cancel pede o cancelamento do job. O status muda para canceled quando a goroutine para
+// (no próximo ponto de verificação: entre atas no scrape, entre parágrafos no enrich).
+func (r *jobRegistry) Cancel(id string) (Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.jobs[id]
+	if !ok {
+		return Job{}, errJobNotFound
+	}
+	if e.job.Status != JobQueued && e.job.Status != JobRunning {
+		return e.job, errJobFinished
+	}
+	e.cancel()
+	job := e.job
+	job.Logs = nil
+	return job, nil
+}
+
+// jobLogWriter acrescenta as linhas escritas pelo logger do job ao seu buffer de logs.
+type jobLogWriter struct {
+	registry *jobRegistry
+	id       string
+}
+
+func (w *jobLogWriter) Write(p []byte) (int, error) {
+	lines := strings.Split(strings.TrimRight(string(p), "\n"), "\n")
+	w.registry.update(w.id, func(j *Job) {
+		j.Logs = append(j.Logs, lines...)
+		if excess := len(j.Logs) - maxJobLogLines; excess > 0 {
+			j.Logs = slices.Delete(j.Logs, 0, excess)
+		}
+	})
+	return len(p), nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Quantidade padrão de parágrafos no journal antes de compactar em dataset_enriched.json.
+// Pode ser alterada pela variável de ambiente JOURNAL_COMPACT_EVERY.
+const defaultJournalCompactEvery = 25
+
+// enrichmentJournal é um log append-only (JSONL) com cada EnrichedParagraph gravado assim que
+// o Gemini responde. Se o processo morrer no meio de uma ata, nenhuma chamada paga é perdida:
+// o journal é reaplicado na próxima execução e compactado no dataset.
+type enrichmentJournal struct {
+	filename string
+	file     *os.File
+	pending  int
+}
+
+// journalFilename deriva o nome do journal a partir do dataset (dataset_enriched.journal.jsonl).
+func journalFilename(datasetFilename string) string {
+	return strings.TrimSuffix(datasetFilename, ".json") + ".journal.jsonl"
+}
+
+func journalCompactEvery() int {
+	if val, err := strconv.Atoi(os.Getenv("JOURNAL_COMPACT_EVERY")); err == nil && val > 0 {
+		return val
+	}
+	return defaultJournalCompactEvery
+}
+
+func openEnrichmentJournal(filename string) (*enrichmentJournal, error) {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &enrichmentJournal{filename: filename, file: file}, nil
+}
+
+// Append grava o parágrafo em uma linha e faz fsync antes de retornar.
+func (j *enrichmentJournal) Append(p EnrichedParagraph) error {
+	line, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := j.file.Sync(); err != nil {
+		return err
+	}
+	j.pending++
+	return nil
+}
+
+// Pending retorna quantos parágrafos foram gravados no journal desde a última compactação.
+func (j *enrichmentJournal) Pending() int {
+	return j.pending
+}
+
+// Compact salva o dataset completo (de forma atômica) e só então esvazia o journal.
+// Se o processo cair entre os dois passos, a reaplicação descarta as entradas já presentes.
+func (j *enrichmentJournal) Compact(datasetFilename string, data []EnrichedParagraph) error {
+	if err := SaveEnrichedData(datasetFilename, data); err != nil {
+		return err
+	}
+	if err := j.file.Truncate(0); err != nil {
+		return fmt.Errorf("falha ao esvaziar journal %s: %w", j.filename, err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return err
+	}
+	j.pending = 0
+	return nil
+}
+
+func (j *enrichmentJournal) Close() error {
+	return j.file.Close()
+}
+
+// replayJournal lê as entradas do journal. Linhas inválidas (ex: última linha truncada por
+// uma queda durante a escrita) são ignoradas com aviso. Journal inexistente não é erro.
+func replayJournal(filename string) ([]EnrichedParagraph, error) {
+	file, err := os.Open(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []EnrichedParagraph
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var p EnrichedParagraph
+		if err := json.Unmarshal([]byte(line), &p); err != nil {
+			log.Printf("AVISO: Linha %d do journal %s inválida (ignorada): %v", lineNum, filename, err)
+			continue
+		}
+		entries = append(entries, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, err
+	}
+	return entries, nil
+}
+
+// mergeJournalEntries adiciona ao dataset as entradas do journal que ainda não estão nele
+// (chave: reunião + parágrafo). Retorna o dataset atualizado e quantas entradas foram recuperadas.
+func mergeJournalEntries(data []EnrichedParagraph, entries []EnrichedParagraph) ([]EnrichedParagraph, int) {
+	type key struct{ meeting, paragraph int }
+	seen := make(map[key]bool, len(data))
+	for _, p := range data {
+		seen[key{p.MeetingNumber, p.ParagraphID}] = true
+	}
+
+	recovered := 0
+	for _, p := range entries {
+		k := key{p.MeetingNumber, p.ParagraphID}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		data = append(data, p)
+		recovered++
+	}
+	return data, recovered
+}
@@ -3,16 +3,24 @@
 // @description API para acesso às atas do COPOM e dados enriquecidos com análise de sentimento via Gemini AI
 // @host localhost:8080
 // @BasePath /
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Exigida apenas quando api_keys.json existe (escopo read para consultas, admin para /admin).
 
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"os"
-	"regexp"
+	"path/filepath"
 	"strconv"
-	"strings"
 	"time"
 
 	_ "github.com/seu-usuario/copom-crawler/docs"
@@ -23,7 +31,20 @@ import (
 )
 
 func main() {
-	modePtr := flag.String("mode", "serve", "Modo de operação: 'scrape', 'enrich', 'serve' ou 'all'")
+	modePtr := flag.String("mode", "serve", "Modo de operação: 'scrape', 'enrich', 'serve', 'daemon', 'keygen', 'migrate', 'validate', 'stats', 'charts', 'diff', 'embed', 'export', 'import' ou 'all'")
+	formatPtr := flag.String("format", exportFormatCSV, "Formato da exportação (-mode=export): 'csv', 'jsonl' ou 'parquet' (aceita lista separada por vírgulas)")
+	datasetPtr := flag.String("dataset", "all", "Dataset a exportar (-mode=export): 'atas', 'enriched' ou 'all'")
+	outPtr := flag.String("out", "export", "Diretório de saída (-mode=export e -mode=charts)")
+	contentPtr := flag.Bool("content", false, "Incluir o texto completo (conteudo das atas e texto dos parágrafos) na exportação")
+	embeddingsPtr := flag.Bool("embeddings", false, "Incluir o embedding de cada parágrafo (de dataset_embeddings.json) na exportação")
+	fromPtr := flag.String("from", "", "Dataset enriquecido de outra máquina a importar (-mode=import)")
+	fromRawPtr := flag.String("from-raw", "", "Dataset bruto de outra máquina a importar (-mode=import, opcional)")
+	numeroPtr := flag.Int("numero", 0, "Número da reunião a comparar (-mode=diff; padrão: a mais recente)")
+	againstPtr := flag.String("against", "prev", "Reunião de referência do diff (-mode=diff): 'prev' ou um número")
+	diffFormatPtr := flag.String("diff-format", "unified", "Formato do diff (-mode=diff): 'unified', 'html' ou 'json'")
+	keyIDPtr := flag.String("key-id", "", "Identificador da chave de API a gerar (-mode=keygen)")
+	scopesPtr := flag.String("scopes", ScopeRead, "Escopos da chave de API (-mode=keygen): 'read', 'admin' ou 'read,admin'")
+	dryRunPtr := flag.Bool("dry-run", false, "Apenas exibir o relatório, sem gravar (-mode=import)")
 	flag.Parse()
 
 	switch *modePtr {
@@ -33,23 +54,77 @@ func main() {
 		runEnricher()
 	case "serve":
 		runServer()
+	case "daemon":
+		runDaemon()
+	case "keygen":
+		runKeygen(*keyIDPtr, *scopesPtr)
+	case "migrate":
+		runMigrate()
+	case "validate":
+		runValidate()
+	case "stats":
+		runStats()
+	case "charts":
+		runCharts(*outPtr)
+	case "diff":
+		runDiff(*numeroPtr, *againstPtr, *diffFormatPtr)
+	case "embed":
+		runEmbed()
+	case "export":
+		runExport(*formatPtr, *datasetPtr, *outPtr, *contentPtr, *embeddingsPtr)
+	case "import":
+		runImport(*fromPtr, *fromRawPtr, *dryRunPtr)
 	case "all":
 		runScraper()
 		runEnricher()
 		runServer()
 	default:
-		log.Fatalf("Modo desconhecido: %s. Use -mode=scrape, -mode=enrich, -mode=serve ou -mode=all", *modePtr)
+		log.Fatalf("Modo desconhecido: %s. Use -mode=scrape, -mode=enrich, -mode=serve, -mode=daemon, -mode=keygen, -mode=migrate, -mode=validate, -mode=stats, -mode=charts, -mode=diff, -mode=embed, -mode=export, -mode=import ou -mode=all", *modePtr)
 	}
 }
 
 func runScraper() {
 	log.Println("=== MODO SCRAPER ===")
+	events, webhooks := cliEventBus()
+	err := scrapeDataset(context.Background(), log.Default(), nil, events)
+	waitWebhooks(webhooks)
+	if err != nil {
+		log.Fatalf("Scraping interrompido: %v", err)
+	}
+}
+
+// cliEventBus liga os eventos dos modos de linha de comando aos webhooks de webhooks.json.
+func cliEventBus() (*eventBus, *webhookDispatcher) {
+	hooks, err := loadWebhooks(webhooksFilename())
+	if err != nil {
+		log.Fatalf("Erro ao carregar webhooks (%s): %v", webhooksFilename(), err)
+	}
+	events := newEventBus()
+	webhooks := newWebhookDispatcher(hooks)
+	events.Subscribe(webhooks.Handle)
+	return events, webhooks
+}
+
+// waitWebhooks espera as entregas pendentes antes de o processo encerrar.
+func waitWebhooks(webhooks *webhookDispatcher) {
+	if pending := len(webhooks.Deliveries("", DeliveryPending)); pending > 0 {
+		log.Printf("Aguardando %d entregas de webhook pendentes...", pending)
+	}
+	webhooks.Wait()
+}
+
+// scrapeDataset atualiza dataset_raw.json com as atas novas. É usado pelo modo 'scrape' e
+// pelos jobs do servidor (POST /admin/jobs/scrape); cada ata nova publica EventAtaScraped em events.
+func scrapeDataset(ctx context.Context, logger *log.Logger, progress progressFunc, events *eventBus) error {
 	filename := "dataset_raw.json"
 
 	// Carregar dados existentes
 	existingAtas, err := LoadAtas(filename)
-	if err != nil {
-		log.Printf("Erro ao carregar %s (será criado novo): %v", filename, err)
+	if errors.Is(err, ErrDatasetNotFound) {
+		logger.Printf("%s não encontrado (será criado novo).", filename)
+	} else if err != nil {
+		// Nunca sobrescrever um dataset ilegível com uma lista vazia
+		return fmt.Errorf("erro ao carregar %s: %w. %s", filename, err, datasetLoadHint(filename, err))
 	}
 
 	existingMap := make(map[int]bool)
@@ -58,7 +133,7 @@ func runScraper() {
 			existingMap[ata.NumeroReuniao] = true
 		}
 	}
-	log.Printf("Carregadas %d atas existentes.", len(existingAtas))
+	logger.Printf("Carregadas %d atas existentes.", len(existingAtas))
 
 	// Callback para salvar a cada nova ata
 	onSave := func(newAta CopomAta) error {
@@ -76,77 +151,79 @@ func runScraper() {
 		}
 		// Salvar tudo no disco
 		// (Ineficiente para grandes volumes, mas seguro e simples para <100MB)
-		return SaveAtas(filename, existingAtas)
+		if err := SaveAtas(filename, existingAtas); err != nil {
+			return err
+		}
+		if !existingMap[newAta.NumeroReuniao] && newAta.Conteudo != "" && !newAta.FalhaNoParse {
+			events.Publish(EventAtaScraped, newMeetingEvent(newAta, nil))
+		}
+		return nil
 	}
 
-	if err := scrapeCopomAtas(existingMap, onSave); err != nil {
-		log.Printf("Erro durante o scraping: %v", err)
+	if err := scrapeCopomAtas(ctx, logger, progress, existingMap, onSave); err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		logger.Printf("Erro durante o scraping: %v", err)
 	}
-	log.Println("Scraping finalizado.")
+	logger.Println("Scraping finalizado.")
+	return nil
 }
 
 func runEnricher() {
 	log.Println("=== MODO ENRICHER (GEMINI) ===")
+	events, webhooks := cliEventBus()
+	err := enrichDataset(context.Background(), log.Default(), nil, events)
+	waitWebhooks(webhooks)
+	if err != nil {
+		log.Fatalf("Enrichment interrompido: %v", err)
+	}
+}
+
+// enrichDataset enriquece com o Gemini os parágrafos ainda não processados. É usado pelo
+// modo 'enrich' e pelos jobs do servidor (POST /admin/jobs/enrich); o cancelamento de ctx
+// é verificado entre parágrafos, e o que já foi enriquecido fica salvo no journal. Cada
+// reunião concluída sem interrupção publica EventEnrichmentCompleted em events.
+func enrichDataset(ctx context.Context, logger *log.Logger, progress progressFunc, events *eventBus) error {
 	rawFilename := "dataset_raw.json"
 	enrichedFilename := "dataset_enriched.json"
 
 	rawAtas, err := LoadAtas(rawFilename)
-	if err != nil {
-		log.Fatalf("Erro ao carregar %s: %v. Execute o modo 'scrape' primeiro.", rawFilename, err)
+	if errors.Is(err, ErrDatasetNotFound) {
+		return fmt.Errorf("erro ao carregar %s: %w. Execute o modo 'scrape' primeiro", rawFilename, err)
+	} else if err != nil {
+		return fmt.Errorf("erro ao carregar %s: %w. %s", rawFilename, err, datasetLoadHint(rawFilename, err))
 	}
 
 	enrichedData, err := LoadEnrichedData(enrichedFilename)
-	if err != nil {
-		log.Printf("Erro ao carregar %s (será criado novo): %v", enrichedFilename, err)
-	}
-
-	// Backfill de IDs se necessário (para compatibilidade com dados antigos)
-	needsSave := false
-	maxGlobalID := 0
-	paraCount := make(map[int]int)
-
-	// Primeiro passo: identificar IDs existentes para não sobrescrever incorretamente
-	for _, item := range enrichedData {
-		if item.GlobalID > maxGlobalID {
-			maxGlobalID = item.GlobalID
-		}
-		if item.ParagraphID > paraCount[item.MeetingNumber] {
-			paraCount[item.MeetingNumber] = item.ParagraphID
-		}
+	if errors.Is(err, ErrDatasetNotFound) {
+		logger.Printf("%s não encontrado (será criado novo).", enrichedFilename)
+	} else if err != nil {
+		return fmt.Errorf("erro ao carregar %s: %w. %s", enrichedFilename, err, datasetLoadHint(enrichedFilename, err))
 	}
 
-	// Criar mapa de URLs das atas brutas para backfill
-	meetingURLMap := make(map[int]string)
-	for _, ata := range rawAtas {
-		meetingURLMap[ata.NumeroReuniao] = ata.URL
+	// Recuperar parágrafos enriquecidos que ficaram apenas no journal (execução interrompida)
+	journalFile := journalFilename(enrichedFilename)
+	journalEntries, err := replayJournal(journalFile)
+	if err != nil {
+		// Não seguir: a compactação truncaria o journal e perderia as entradas não lidas
+		return fmt.Errorf("erro ao ler journal %s: %w. Corrija ou mova o arquivo antes de continuar", journalFile, err)
 	}
-
-	// Segundo passo: preencher zeros e URLs faltantes
-	for i := range enrichedData {
-		if enrichedData[i].GlobalID == 0 {
-			maxGlobalID++
-			enrichedData[i].GlobalID = maxGlobalID
-			needsSave = true
-		}
-		if enrichedData[i].ParagraphID == 0 {
-			paraCount[enrichedData[i].MeetingNumber]++
-			enrichedData[i].ParagraphID = paraCount[enrichedData[i].MeetingNumber]
-			needsSave = true
-		}
-		if enrichedData[i].URL == "" {
-			if url, ok := meetingURLMap[enrichedData[i].MeetingNumber]; ok {
-				enrichedData[i].URL = url
-				needsSave = true
-			}
-		}
+	journal, err := openEnrichmentJournal(journalFile)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir journal %s: %w", journalFile, err)
 	}
-
-	if needsSave {
-		log.Println("Atualizando dataset com IDs sequenciais (Backfill)...")
-		if err := SaveEnrichedData(enrichedFilename, enrichedData); err != nil {
-			log.Printf("Erro ao salvar backfill: %v", err)
+	defer journal.Close()
+
+	var recovered int
+	enrichedData, recovered = mergeJournalEntries(enrichedData, journalEntries)
+	if len(journalEntries) > 0 {
+		logger.Printf("Journal: %d entradas lidas, %d parágrafos recuperados.", len(journalEntries), recovered)
+		if err := journal.Compact(enrichedFilename, enrichedData); err != nil {
+			return fmt.Errorf("erro ao compactar journal em %s: %w", enrichedFilename, err)
 		}
 	}
+	compactEvery := journalCompactEvery()
 
 	// Mapa para rastrear parágrafos já processados: MeetingNumber -> ParagraphID -> bool
 	processedMap := make(map[int]map[int]bool)
@@ -164,9 +241,9 @@ func runEnricher() {
 		}
 	}
 
-	log.Printf("Total de atas brutas: %d", len(rawAtas))
-	log.Printf("Total de parágrafos já enriquecidos: %d", len(enrichedData))
-	log.Printf("Próximo Global ID: %d", nextGlobalID)
+	logger.Printf("Total de atas brutas: %d", len(rawAtas))
+	logger.Printf("Total de parágrafos já enriquecidos: %d", len(enrichedData))
+	logger.Printf("Próximo Global ID: %d", nextGlobalID)
 
 	// Configuração de limite (opcional)
 	maxMeetingsStr := os.Getenv("MAX_MEETINGS")
@@ -177,6 +254,18 @@ func runEnricher() {
 		}
 	}
 
+	// Progresso em atas com conteúdo (limitado por MAX_MEETINGS, quando definido)
+	totalAtas := 0
+	for _, ata := range rawAtas {
+		if ata.Conteudo != "" {
+			totalAtas++
+		}
+	}
+	if maxMeetings > 0 && maxMeetings < totalAtas {
+		totalAtas = maxMeetings
+	}
+	analyzed := 0
+
 	count := 0
 	for _, ata := range rawAtas {
 		// Não pulamos a ata inteira aqui baseada no enrichedMap antigo, pois precisamos checar parágrafo a parágrafo.
@@ -184,13 +273,13 @@ func runEnricher() {
 		// Por simplificação, vamos gerar os parágrafos e checar um a um.
 
 		if maxMeetings > 0 && count >= maxMeetings {
-			log.Printf("Atingido limite de processamento de atas (%d). Parando.", maxMeetings)
+			logger.Printf("Atingido limite de processamento de atas (%d). Parando.", maxMeetings)
 			break
 		}
 
 		// Limite de segurança total de parágrafos (ex: 1000)
 		if len(enrichedData) >= 1000 {
-			log.Printf("Atingido limite total de 1000 parágrafos enriquecidos. Parando.")
+			logger.Printf("Atingido limite total de 1000 parágrafos enriquecidos. Parando.")
 			break
 		}
 
@@ -198,41 +287,19 @@ func runEnricher() {
 			continue
 		}
 
-		log.Printf("Analisando Ata %d...", ata.NumeroReuniao)
+		progress.report(analyzed, totalAtas)
+		analyzed++
+		logger.Printf("Analisando Ata %d...", ata.NumeroReuniao)
 
 		var textContent string
 		if ata.FalhaNoParse {
 			// Remover tags HTML simples para extrair texto
-			re := regexp.MustCompile(`<[^>]*>`)
-			textContent = re.ReplaceAllString(ata.Conteudo, "\n")
-			textContent = strings.ReplaceAll(textContent, "&nbsp;", " ")
-			textContent = strings.ReplaceAll(textContent, "&amp;", "&")
+			textContent = stripHTML(ata.Conteudo)
 		} else {
 			textContent = ata.Conteudo
 		}
 
-		// Quebrar em linhas e agregar parágrafos
-		rawLines := strings.Split(textContent, "\n")
-		var paragraphs []string
-		var currentBuffer strings.Builder
-
-		for _, line := range rawLines {
-			line = strings.TrimSpace(line)
-			if line == "" {
-				continue
-			}
-			if currentBuffer.Len() > 0 {
-				currentBuffer.WriteString(" ")
-			}
-			currentBuffer.WriteString(line)
-			if currentBuffer.Len() >= 200 {
-				paragraphs = append(paragraphs, currentBuffer.String())
-				currentBuffer.Reset()
-			}
-		}
-		if currentBuffer.Len() > 0 {
-			paragraphs = append(paragraphs, currentBuffer.String())
-		}
+		paragraphs := splitParagraphs(textContent)
 
 		processedParagraphs := 0
 		newlyEnrichedCount := 0
@@ -255,15 +322,21 @@ func runEnricher() {
 			if processedMap[ata.NumeroReuniao][paragraphID] {
 				continue
 			}
+			if ctx.Err() != nil {
+				break
+			}
 
 			if (i+1)%5 == 0 || i == 0 {
-				log.Printf("  Processando parágrafo %d/%d da Ata %d...", paragraphID, totalParagraphs, ata.NumeroReuniao)
+				logger.Printf("  Processando parágrafo %d/%d da Ata %d...", paragraphID, totalParagraphs, ata.NumeroReuniao)
 			}
 
-			prediction, err := callGeminiAPI(p, ata.ValorDolar, ata.ValorIPCA)
+			analysis, err := callGeminiAPI(ctx, p, ata.ValorDolar, ata.ValorIPCA)
 			if err != nil {
-				log.Printf("Erro ao chamar Gemini para reunião %d: %v", ata.NumeroReuniao, err)
-				time.Sleep(5 * time.Second)
+				if ctx.Err() != nil {
+					break
+				}
+				logger.Printf("Erro ao chamar Gemini para reunião %d: %v", ata.NumeroReuniao, err)
+				sleepContext(ctx, 5*time.Second)
 				continue
 			}
 
@@ -276,7 +349,8 @@ func runEnricher() {
 				DollarValue:   ata.ValorDolar,
 				IPCAValue:     ata.ValorIPCA,
 				Paragraph:     p,
-				Prediction:    prediction,
+				Topics:        paragraphTopics(p, analysis.Topics),
+				Prediction:    analysis.GeminiPrediction,
 			}
 			enrichedData = append(enrichedData, enriched)
 			processedMap[ata.NumeroReuniao][paragraphID] = true
@@ -284,63 +358,509 @@ func runEnricher() {
 			processedParagraphs++
 			newlyEnrichedCount++
 
+			events.Publish(EventParagraphEnriched, ParagraphEvent{
+				GlobalID:      enriched.GlobalID,
+				MeetingNumber: enriched.MeetingNumber,
+				ParagraphID:   enriched.ParagraphID,
+				Topics:        enriched.Topics,
+				Prediction:    enriched.Prediction,
+			})
+
+			// Persistir imediatamente no journal para não perder a chamada paga
+			if err := journal.Append(enriched); err != nil {
+				logger.Printf("Erro ao gravar parágrafo %d da reunião %d no journal: %v", paragraphID, ata.NumeroReuniao, err)
+			}
+			if journal.Pending() >= compactEvery {
+				if err := journal.Compact(enrichedFilename, enrichedData); err != nil {
+					logger.Printf("Erro ao compactar journal: %v", err)
+				}
+			}
+
 			// Rate limit
-			time.Sleep(2 * time.Second)
+			sleepContext(ctx, 2*time.Second)
 		}
 
 		if newlyEnrichedCount > 0 {
-			if err := SaveEnrichedData(enrichedFilename, enrichedData); err != nil {
-				log.Printf("Erro ao salvar dados enriquecidos: %v", err)
+			if err := journal.Compact(enrichedFilename, enrichedData); err != nil {
+				logger.Printf("Erro ao salvar dados enriquecidos: %v", err)
 			} else {
-				log.Printf("Ata %d salva com %d novos parágrafos enriquecidos.", ata.NumeroReuniao, newlyEnrichedCount)
+				logger.Printf("Ata %d salva com %d novos parágrafos enriquecidos.", ata.NumeroReuniao, newlyEnrichedCount)
 				count++
+				if ctx.Err() == nil {
+					events.Publish(EventEnrichmentCompleted, newMeetingEvent(ata, meetingParagraphs(enrichedData, ata.NumeroReuniao)))
+				}
 			}
 		} else {
-			log.Printf("Ata %d: nenhum novo parágrafo para enriquecer.", ata.NumeroReuniao)
+			logger.Printf("Ata %d: nenhum novo parágrafo para enriquecer.", ata.NumeroReuniao)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if ctx.Err() == nil {
+		progress.report(totalAtas, totalAtas)
+	}
+	logger.Println("Enrichment finalizado.")
+
+	// Novos parágrafos ganham embedding logo após o enriquecimento (não bloqueia em caso de erro)
+	if count > 0 {
+		if err := refreshEmbeddingsFile(embeddingsFilename, enrichedData); err != nil {
+			logger.Printf("Erro ao atualizar embeddings: %v. Execute -mode=embed.", err)
 		}
 	}
-	log.Println("Enrichment finalizado.")
+	return ctx.Err()
 }
 
-func runServer() {
-	log.Println("=== MODO SERVER ===")
+func runEmbed() {
+	log.Println("=== MODO EMBED ===")
+	enrichedData, err := readEnrichedReadOnly("dataset_enriched.json")
+	if err != nil {
+		log.Fatalf("Erro ao carregar dataset_enriched.json: %v", err)
+	}
+	if err := refreshEmbeddingsFile(embeddingsFilename, enrichedData); err != nil {
+		log.Fatalf("Erro ao calcular embeddings: %v", err)
+	}
+}
 
-	// Carregar dados das atas
-	store := newAtaStore()
-	atas, err := LoadAtas("dataset_raw.json")
+func runMigrate() {
+	log.Println("=== MODO MIGRATE ===")
+	rawFilename := "dataset_raw.json"
+	enrichedFilename := "dataset_enriched.json"
+
+	rawAtas, rawVersion, err := readAtasFile(rawFilename)
+	rawExists := err == nil
+	if err != nil && !errors.Is(err, ErrDatasetNotFound) {
+		log.Fatalf("Erro ao carregar %s: %v. %s", rawFilename, err, datasetLoadHint(rawFilename, err))
+	}
+
+	enrichedData, enrichedVersion, err := readEnrichedFile(enrichedFilename)
+	enrichedExists := err == nil
+	if err != nil && !errors.Is(err, ErrDatasetNotFound) {
+		log.Fatalf("Erro ao carregar %s: %v. %s", enrichedFilename, err, datasetLoadHint(enrichedFilename, err))
+	}
+
+	// Parágrafos pendentes no journal também precisam passar pelas migrações
+	journalFile := journalFilename(enrichedFilename)
+	journalEntries, err := replayJournal(journalFile)
+	if err != nil {
+		log.Fatalf("Erro ao ler journal %s: %v", journalFile, err)
+	}
+	enrichedData, recovered := mergeJournalEntries(enrichedData, journalEntries)
+	if recovered > 0 {
+		log.Printf("Journal: %d parágrafos recuperados antes da migração.", recovered)
+		enrichedExists = true
+	}
+
+	from := currentSchemaVersion
+	if rawExists && rawVersion < from {
+		from = rawVersion
+	}
+	if enrichedExists && enrichedVersion < from {
+		from = enrichedVersion
+	}
+	for _, v := range []int{rawVersion, enrichedVersion} {
+		if v > currentSchemaVersion {
+			log.Fatalf("Dataset no schema %d, mas esta versão do crawler suporta até %d.", v, currentSchemaVersion)
+		}
+	}
+
+	log.Printf("%s: schema %d | %s: schema %d | atual: %d", rawFilename, rawVersion, enrichedFilename, enrichedVersion, currentSchemaVersion)
+	if from == currentSchemaVersion && recovered == 0 {
+		log.Println("Datasets já estão no schema atual. Nada a fazer.")
+		return
+	}
+
+	bundle := &datasetBundle{Atas: rawAtas, Enriched: enrichedData}
+	applied, err := applyMigrations(bundle, from)
+	for _, m := range applied {
+		log.Printf("Migração %d aplicada: %s", m.Version, m.Description)
+	}
+	if err != nil {
+		log.Fatalf("Erro ao migrar (nenhum arquivo foi alterado): %v", err)
+	}
+
+	if rawExists && rawVersion < currentSchemaVersion {
+		if err := SaveAtas(rawFilename, bundle.Atas); err != nil {
+			log.Fatalf("Erro ao salvar %s: %v", rawFilename, err)
+		}
+		log.Printf("%s migrado para o schema %d.", rawFilename, currentSchemaVersion)
+	}
+	if enrichedExists {
+		journal, err := openEnrichmentJournal(journalFile)
+		if err != nil {
+			log.Fatalf("Erro ao abrir journal %s: %v", journalFile, err)
+		}
+		defer journal.Close()
+		if err := journal.Compact(enrichedFilename, bundle.Enriched); err != nil {
+			log.Fatalf("Erro ao salvar %s: %v", enrichedFilename, err)
+		}
+		log.Printf("%s migrado para o schema %d.", enrichedFilename, currentSchemaVersion)
+	}
+	log.Println("Migração finalizada.")
+}
+
+func runValidate() {
+	log.Println("=== MODO VALIDATE ===")
+	rawFilename := "dataset_raw.json"
+	enrichedFilename := "dataset_enriched.json"
+	report := &validationReport{}
+
+	// Arquivos em schemas antigos também podem ser validados (antes do migrate)
+	rawAtas, rawVersion, err := readAtasFile(rawFilename)
+	if errors.Is(err, ErrDatasetNotFound) {
+		report.add(severityWarning, rawFilename, "arquivo não encontrado", "verificação de parágrafos órfãos ignorada")
+		rawAtas = nil
+	} else if err != nil {
+		report.add(severityError, rawFilename, "arquivo ilegível", err.Error())
+		rawAtas = nil
+	} else {
+		if err := checkSchemaVersion(rawFilename, rawVersion); err != nil {
+			report.add(severityWarning, rawFilename, "schema", err.Error())
+		}
+		validateAtas(report, rawAtas)
+	}
+
+	enrichedData, enrichedVersion, err := readEnrichedFile(enrichedFilename)
+	if errors.Is(err, ErrDatasetNotFound) {
+		report.add(severityWarning, enrichedFilename, "arquivo não encontrado", "")
+	} else if err != nil {
+		report.add(severityError, enrichedFilename, "arquivo ilegível", err.Error())
+	} else {
+		if err := checkSchemaVersion(enrichedFilename, enrichedVersion); err != nil {
+			report.add(severityWarning, enrichedFilename, "schema", err.Error())
+		}
+		validateEnriched(report, enrichedData, rawAtas)
+	}
+
+	report.Print(os.Stdout)
+	if report.Errors() > 0 {
+		os.Exit(1)
+	}
+}
+
+func runStats() {
+	log.Println("=== MODO STATS ===")
+	atas, err := readAtasReadOnly("dataset_raw.json")
+	if err != nil && !errors.Is(err, ErrDatasetNotFound) {
+		log.Fatalf("Erro ao carregar dataset_raw.json: %v", err)
+	}
+	enrichedData, err := readEnrichedReadOnly("dataset_enriched.json")
+	if err != nil && !errors.Is(err, ErrDatasetNotFound) {
+		log.Fatalf("Erro ao carregar dataset_enriched.json: %v", err)
+	}
+	computeStats(atas, enrichedData).Print(os.Stdout)
+}
+
+func runCharts(outDir string) {
+	log.Println("=== MODO CHARTS ===")
+	atas, err := readAtasReadOnly("dataset_raw.json")
 	if err != nil {
-		log.Printf("AVISO: Não foi possível carregar dataset_raw.json: %v", err)
+		log.Fatalf("Erro ao carregar dataset_raw.json: %v", err)
 	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		log.Fatalf("Erro ao criar diretório %s: %v", outDir, err)
+	}
+
+	data := buildChartData(atas, "", "")
+	for i, spec := range chartSpecs {
+		filename := filepath.Join(outDir, fmt.Sprintf("grafico_%d_%s.svg", i+1, spec.Name))
+		if err := os.WriteFile(filename, []byte(renderChart(spec, data)), 0644); err != nil {
+			log.Fatalf("Erro ao gravar %s: %v", filename, err)
+		}
+		log.Printf("Salvo: %s", filename)
+	}
+}
 
-	store.mu.Lock()
-	store.atas = atas
+func runDiff(numero int, against, format string) {
+	atas, err := readAtasReadOnly("dataset_raw.json")
+	if err != nil {
+		log.Fatalf("Erro ao carregar dataset_raw.json: %v", err)
+	}
+	porNumero := make(map[int]CopomAta, len(atas))
 	for _, ata := range atas {
 		if ata.NumeroReuniao != 0 {
-			store.atasPorNumero[ata.NumeroReuniao] = ata
+			porNumero[ata.NumeroReuniao] = ata
+		}
+	}
+	// Sem -numero, compara a reunião mais recente
+	if numero == 0 {
+		for n := range porNumero {
+			numero = max(numero, n)
+		}
+	}
+
+	from, to, err := resolveDiffPair(porNumero, numero, against)
+	if err != nil {
+		log.Fatal(err)
+	}
+	diff := diffAtas(from, to)
+	switch format {
+	case "unified":
+		diff.WriteUnified(os.Stdout)
+	case "html":
+		diff.WriteHTML(os.Stdout)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diff); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		log.Fatalf("Formato de diff desconhecido: %s. Use unified, html ou json.", format)
+	}
+}
+
+func runExport(format, dataset, outDir string, includeContent, includeEmbedding bool) {
+	log.Println("=== MODO EXPORT ===")
+	formats, err := parseExportFormats(format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var tables []*exportTable
+	if dataset == "atas" || dataset == "all" {
+		atas, err := readAtasReadOnly("dataset_raw.json")
+		if err != nil {
+			log.Fatalf("Erro ao carregar dataset_raw.json: %v", err)
+		}
+		tables = append(tables, atasTable(atas, includeContent))
+	}
+	if dataset == "enriched" || dataset == "all" {
+		enrichedData, err := readEnrichedReadOnly("dataset_enriched.json")
+		if err != nil {
+			log.Fatalf("Erro ao carregar dataset_enriched.json: %v", err)
+		}
+		if includeEmbedding {
+			set, err := loadEmbeddings(embeddingsFilename)
+			if err != nil {
+				log.Fatalf("Erro ao carregar %s: %v", embeddingsFilename, err)
+			}
+			enrichedData = set.withEmbeddings(enrichedData)
+		}
+		tables = append(tables, enrichedTable(enrichedData, includeContent, includeEmbedding))
+	}
+	if len(tables) == 0 {
+		log.Fatalf("Dataset desconhecido: %s. Use -dataset=atas, -dataset=enriched ou -dataset=all", dataset)
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		log.Fatalf("Erro ao criar diretório %s: %v", outDir, err)
+	}
+	for _, table := range tables {
+		for _, f := range formats {
+			path := filepath.Join(outDir, table.Name+"."+f)
+			var buf bytes.Buffer
+			if err := writeTable(&buf, table, f); err != nil {
+				log.Fatalf("Erro ao exportar %s: %v", path, err)
+			}
+			if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+				log.Fatalf("Erro ao gravar %s: %v", path, err)
+			}
+			log.Printf("%s: %d linhas, %d colunas.", path, len(table.Rows), len(table.Columns))
 		}
 	}
-	store.mu.Unlock()
-	log.Printf("Servindo %d atas.", len(store.atas))
+	log.Println("Exportação finalizada.")
+}
+
+func runImport(fromEnriched, fromRaw string, dryRun bool) {
+	log.Println("=== MODO IMPORT ===")
+	rawFilename := "dataset_raw.json"
+	enrichedFilename := "dataset_enriched.json"
+	if fromEnriched == "" && fromRaw == "" {
+		log.Fatal("Informe o dataset a importar: -from=<dataset_enriched.json> e/ou -from-raw=<dataset_raw.json>")
+	}
 
-	// Carregar dados enriquecidos
+	localAtas, err := LoadAtas(rawFilename)
+	if err != nil && !errors.Is(err, ErrDatasetNotFound) {
+		log.Fatalf("Erro ao carregar %s: %v. %s", rawFilename, err, datasetLoadHint(rawFilename, err))
+	}
+	localEnriched, err := LoadEnrichedData(enrichedFilename)
+	if err != nil && !errors.Is(err, ErrDatasetNotFound) {
+		log.Fatalf("Erro ao carregar %s: %v. %s", enrichedFilename, err, datasetLoadHint(enrichedFilename, err))
+	}
+
+	// Parágrafos pendentes no journal local entram na mesclagem
+	journalFile := journalFilename(enrichedFilename)
+	journalEntries, err := replayJournal(journalFile)
+	if err != nil {
+		log.Fatalf("Erro ao ler journal %s: %v", journalFile, err)
+	}
+	localEnriched, _ = mergeJournalEntries(localEnriched, journalEntries)
+
+	// O dataset importado pode estar em um schema antigo: migrar em memória antes de mesclar
+	incoming := &datasetBundle{}
+	incomingVersion := currentSchemaVersion
+	if fromRaw != "" {
+		atas, version, err := readAtasFile(fromRaw)
+		if err != nil {
+			log.Fatalf("Erro ao carregar %s: %v", fromRaw, err)
+		}
+		incoming.Atas = atas
+		incomingVersion = version
+	}
+	if fromEnriched != "" {
+		data, version, err := readEnrichedFile(fromEnriched)
+		if err != nil {
+			log.Fatalf("Erro ao carregar %s: %v", fromEnriched, err)
+		}
+		incoming.Enriched = data
+		if version < incomingVersion {
+			incomingVersion = version
+		}
+	}
+	if incomingVersion > currentSchemaVersion {
+		log.Fatalf("Dataset importado está no schema %d, mas esta versão do crawler suporta até %d.", incomingVersion, currentSchemaVersion)
+	}
+	if _, err := applyMigrations(incoming, incomingVersion); err != nil {
+		log.Fatalf("Erro ao migrar dataset importado: %v", err)
+	}
+
+	result := &importResult{}
+	mergeAtas(result, localAtas, incoming.Atas)
+	mergeEnrichedDatasets(result, localEnriched, incoming.Enriched)
+	result.Print(os.Stdout)
+
+	if dryRun {
+		log.Println("Dry-run: nenhum arquivo foi alterado.")
+		return
+	}
+	if fromRaw != "" && (result.AtasAdded > 0 || result.AtasReplaced > 0) {
+		if err := SaveAtas(rawFilename, result.Atas); err != nil {
+			log.Fatalf("Erro ao salvar %s: %v", rawFilename, err)
+		}
+	}
+	if fromEnriched != "" {
+		journal, err := openEnrichmentJournal(journalFile)
+		if err != nil {
+			log.Fatalf("Erro ao abrir journal %s: %v", journalFile, err)
+		}
+		defer journal.Close()
+		if err := journal.Compact(enrichedFilename, result.Enriched); err != nil {
+			log.Fatalf("Erro ao salvar %s: %v", enrichedFilename, err)
+		}
+	}
+	log.Println("Importação finalizada.")
+}
+
+func runServer() {
+	log.Println("=== MODO SERVER ===")
+	router, _, _ := setupServer()
+
+	log.Println("Servidor de API iniciado em http://localhost:8080")
+	router.Run(":8080")
+}
+
+// runKeygen gera uma chave de API e imprime a entrada para api_keys.json. A chave em si só
+// aparece aqui: o arquivo guarda apenas o hash.
+func runKeygen(id, scopeSpec string) {
+	if id == "" {
+		log.Fatalf("Informe o identificador da chave com -key-id (ex: -key-id=dashboard).")
+	}
+	scopes, err := parseScopes(scopeSpec)
+	if err != nil {
+		log.Fatalf("Escopos inválidos: %v", err)
+	}
+	key, err := newAPIKey()
+	if err != nil {
+		log.Fatalf("Erro ao gerar chave: %v", err)
+	}
+	entry, _ := json.MarshalIndent(APIKey{ID: id, Hash: hashAPIKey(key), Scopes: scopes}, "  ", "  ")
+	fmt.Printf("Chave de API (guarde agora, ela não é armazenada):\n\n  %s\n\n", key)
+	fmt.Printf("Adicione ao array em %s:\n\n  %s\n", apiKeysFilename(), entry)
+}
+
+// runDaemon serve a API como runServer e, em paralelo, agenda scrape e enrich para logo após
+// a publicação esperada de cada ata do calendário de reuniões.
+func runDaemon() {
+	log.Println("=== MODO DAEMON ===")
+	filename := calendarFilename()
+	calendar, err := loadMeetingCalendar(filename)
+	if err != nil {
+		log.Fatalf("Erro ao carregar o calendário de reuniões (%s): %v. Defina COPOM_CALENDAR ou crie o arquivo.", filename, err)
+	}
+	log.Printf("Calendário %s: %d reuniões, ata esperada %d dias úteis após cada reunião.", filename, len(calendar.Meetings), calendar.PublicationBusinessDays)
+
+	router, store, jobs := setupServer()
+	sched := newScheduler(calendar, filename, store, jobs)
+	router.GET("/admin/schedule", GetSchedule(sched))
+	go sched.Run(context.Background())
+
+	log.Println("Servidor de API iniciado em http://localhost:8080")
+	router.Run(":8080")
+}
+
+// setupServer carrega os datasets, registra as rotas e retorna o router junto com o store de
+// atas e o registro de jobs (usados pelo agendador do modo 'daemon').
+func setupServer() (*gin.Engine, *ataStore, *jobRegistry) {
+	// Eventos do pipeline, transmitidos em GET /events
+	events := newEventBus()
+	stream := newEventStream(eventsBufferSize())
+	events.Subscribe(stream.Handle)
+
+	// Carregar datasets (e recarregar quando os arquivos mudarem em disco)
+	store := newAtaStore()
 	enriched := newEnrichedStore()
-	enrichedData, err := LoadEnrichedData("dataset_enriched.json")
+	reloader := newDatasetReloader(store, enriched, "dataset_raw.json", "dataset_enriched.json")
+	search := newSearchIndex()
+	reloader.OnReload(func() { search.rebuild(store.all(), enriched.all()) })
+	similar := newSimilarityIndex()
+	reloader.OnReload(func() { similar.rebuild(enriched.all(), embeddingsFilename) })
+	facts := newFactIndex()
+	reloader.OnReload(func() { facts.rebuild(store.all()) })
+	guidance := newGuidanceIndex()
+	reloader.OnReload(func() { guidance.rebuild(store.all()) })
+	reloader.OnReload(func() {
+		events.Publish(EventDatasetReloaded, ReloadResult{Atas: len(store.all()), Paragraphs: len(enriched.all()), ReloadedAt: time.Now()})
+	})
+	result, err := reloader.Reload()
+	if err != nil {
+		log.Printf("AVISO: Não foi possível carregar os datasets: %v", err)
+	}
+	log.Printf("Servindo %d atas.", result.Atas)
+	log.Printf("Servindo %d parágrafos enriquecidos.", result.Paragraphs)
+	reloader.Watch(reloadInterval())
+
+	// Eventos de reunião também vão para os webhooks de webhooks.json
+	hooks, err := loadWebhooks(webhooksFilename())
 	if err != nil {
-		log.Printf("AVISO: Não foi possível carregar dataset_enriched.json: %v", err)
+		log.Fatalf("Erro ao carregar webhooks (%s): %v", webhooksFilename(), err)
+	}
+	webhooks := newWebhookDispatcher(hooks)
+	events.Subscribe(webhooks.Handle)
+	if len(hooks) > 0 {
+		log.Printf("%d webhooks configurados.", len(hooks))
 	}
 
-	enriched.mu.Lock()
-	enriched.paragraphs = enrichedData
-	for _, p := range enrichedData {
-		enriched.byGlobalID[p.GlobalID] = p
-		enriched.byMeetingNumber[p.MeetingNumber] = append(enriched.byMeetingNumber[p.MeetingNumber], p)
+	// Jobs de scrape/enrich disparados pela API; ao terminar, recarrega os datasets
+	jobs := newJobRegistry(map[string]jobRunner{
+		JobScrape: func(ctx context.Context, logger *log.Logger, progress progressFunc) error {
+			return scrapeDataset(ctx, logger, progress, events)
+		},
+		JobEnrich: func(ctx context.Context, logger *log.Logger, progress progressFunc) error {
+			return enrichDataset(ctx, logger, progress, events)
+		},
+	}, events, func(job Job) {
+		if _, err := reloader.Reload(); err != nil {
+			log.Printf("Erro ao recarregar datasets após job %s: %v", job.ID, err)
+		}
+	})
+
+	// Chaves de API (opcional): sem api_keys.json a API fica aberta
+	keys, err := loadAPIKeys(apiKeysFilename())
+	if err != nil {
+		log.Fatalf("Erro ao carregar chaves de API (%s): %v", apiKeysFilename(), err)
+	}
+	if len(keys) == 0 {
+		log.Printf("AVISO: %s não encontrado ou vazio. API sem autenticação; não exponha fora de localhost.", apiKeysFilename())
+	} else {
+		log.Printf("Autenticação por chave de API ativa (%d chaves).", len(keys))
 	}
-	enriched.mu.Unlock()
-	log.Printf("Servindo %d parágrafos enriquecidos.", len(enriched.paragraphs))
 
 	// Configurar router
 	gin.SetMode(gin.ReleaseMode)
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gin.LoggerWithConfig(gin.LoggerConfig{Formatter: redactedLogFormatter}), gin.Recovery())
+	router.Use(newAPIAuth(keys).Middleware())
 
 	// Swagger UI
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -349,12 +869,43 @@ func runServer() {
 	router.GET("/atas", ListAtas(store))
 	router.GET("/atas/numeros", ListAtaNumeros(store))
 	router.GET("/atas/:numero", GetAtaByNumero(store))
+	router.GET("/atas/:numero/diff", GetAtaDiff(store))
+	router.GET("/atas/:numero/facts", GetAtaFacts(store, facts))
 
 	// Endpoints de dados enriquecidos
-	router.GET("/enriched", ListEnriched(enriched))
-	router.GET("/enriched/:id", GetEnrichedByID(enriched))
-	router.GET("/enriched/meeting/:numero", GetEnrichedByMeeting(enriched))
-
-	log.Println("Servidor de API iniciado em http://localhost:8080")
-	router.Run(":8080")
+	router.GET("/enriched", ListEnriched(enriched, similar))
+	router.GET("/enriched/:id", GetEnrichedByID(enriched, similar))
+	router.GET("/enriched/:id/similar", GetSimilarParagraphs(enriched, similar))
+	router.GET("/enriched/meeting/:numero", GetEnrichedByMeeting(enriched, similar))
+
+	// Busca textual
+	router.GET("/search", SearchDocuments(search))
+
+	// Feeds das atas
+	router.GET("/feed.atom", GetAtomFeed(store, facts))
+	router.GET("/feed.rss", GetRSSFeed(store, facts))
+
+	// Eventos do pipeline (SSE)
+	router.GET("/events", StreamEvents(stream))
+
+	// Séries temporais e estatísticas
+	router.GET("/timeseries", GetTimeSeries(store, enriched))
+	router.GET("/stats", GetStats(store, enriched))
+	router.GET("/guidance", GetGuidance(guidance))
+
+	// Gráficos (SVG)
+	router.GET("/charts", ListCharts())
+	router.GET("/charts/:name", GetChart(store))
+
+	// Administração
+	router.POST("/admin/reload", ReloadDatasets(reloader))
+	router.GET("/admin/jobs", ListJobs(jobs))
+	router.POST("/admin/jobs/scrape", StartJob(jobs, JobScrape))
+	router.POST("/admin/jobs/enrich", StartJob(jobs, JobEnrich))
+	router.GET("/admin/jobs/:id", GetJob(jobs))
+	router.POST("/admin/jobs/:id/cancel", CancelJob(jobs))
+	router.GET("/admin/webhooks", ListWebhooks(webhooks))
+	router.GET("/admin/webhooks/deliveries", ListWebhookDeliveries(webhooks))
+
+	return router, store, jobs
 }
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+// datasetBundle reúne os dois datasets para que uma migração possa cruzar informações
+// (ex: preencher a URL de um parágrafo a partir da ata bruta).
+type datasetBundle struct {
+	Atas     []CopomAta
+	Enriched []EnrichedParagraph
+}
+
+// migration leva os datasets para a versão Version do schema. Como os dois arquivos podem
+// estar em versões diferentes, toda migração deve ser idempotente.
+type migration struct {
+	Version     int
+	Description string
+	Apply       func(b *datasetBundle) error
+}
+
+// migrations é a lista ordenada de migrações registradas. Para alterar a estrutura de
+// CopomAta ou EnrichedParagraph, adicione uma nova entrada no final (nunca edite as antigas).
+var migrations = []migration{
+	{
+		Version:     1,
+		Description: "Envelope {schema_version, data} nos arquivos de dataset",
+		Apply:       func(b *datasetBundle) error { return nil },
+	},
+	{
+		Version:     2,
+		Description: "Backfill de global_id, paragraph_id e url em parágrafos enriquecidos antigos",
+		Apply:       backfillEnrichedIDs,
+	},
+	{
+		Version:     3,
+		Description: "Classificação de tópicos (topics) em parágrafos enriquecidos antigos",
+		Apply:       backfillTopics,
+	},
+}
+
+// currentSchemaVersion é a versão gravada por SaveAtas/SaveEnrichedData.
+var currentSchemaVersion = migrations[len(migrations)-1].Version
+
+// applyMigrations aplica, em ordem, as migrações posteriores à versão from.
+func applyMigrations(b *datasetBundle, from int) ([]migration, error) {
+	var applied []migration
+	for _, m := range migrations {
+		if m.Version <= from {
+			continue
+		}
+		if err := m.Apply(b); err != nil {
+			return applied, fmt.Errorf("migração %d (%s): %w", m.Version, m.Description, err)
+		}
+		applied = append(applied, m)
+	}
+	return applied, nil
+}
+
+// backfillEnrichedIDs preenche IDs e URLs de registros gravados antes desses campos existirem.
+func backfillEnrichedIDs(b *datasetBundle) error {
+	maxGlobalID := 0
+	paraCount := make(map[int]int)
+
+	// Primeiro passo: identificar IDs existentes para não sobrescrever incorretamente
+	for _, item := range b.Enriched {
+		if item.GlobalID > maxGlobalID {
+			maxGlobalID = item.GlobalID
+		}
+		if item.ParagraphID > paraCount[item.MeetingNumber] {
+			paraCount[item.MeetingNumber] = item.ParagraphID
+		}
+	}
+
+	// Criar mapa de URLs das atas brutas para backfill
+	meetingURLMap := make(map[int]string)
+	for _, ata := range b.Atas {
+		meetingURLMap[ata.NumeroReuniao] = ata.URL
+	}
+
+	// Segundo passo: preencher zeros e URLs faltantes
+	filled := 0
+	for i := range b.Enriched {
+		changed := false
+		if b.Enriched[i].GlobalID == 0 {
+			maxGlobalID++
+			b.Enriched[i].GlobalID = maxGlobalID
+			changed = true
+		}
+		if b.Enriched[i].ParagraphID == 0 {
+			paraCount[b.Enriched[i].MeetingNumber]++
+			b.Enriched[i].ParagraphID = paraCount[b.Enriched[i].MeetingNumber]
+			changed = true
+		}
+		if b.Enriched[i].URL == "" {
+			if url, ok := meetingURLMap[b.Enriched[i].MeetingNumber]; ok {
+				b.Enriched[i].URL = url
+				changed = true
+			}
+		}
+		if changed {
+			filled++
+		}
+	}
+
+	log.Printf("  Backfill: %d parágrafos atualizados.", filled)
+	return nil
+}
+
+// backfillTopics classifica por palavras-chave os parágrafos gravados sem tópicos.
+func backfillTopics(b *datasetBundle) error {
+	filled := 0
+	for i := range b.Enriched {
+		if len(b.Enriched[i].Topics) == 0 {
+			b.Enriched[i].Topics = classifyTopicsByKeywords(b.Enriched[i].Paragraph)
+			filled++
+		}
+	}
+	log.Printf("  Tópicos: %d parágrafos classificados.", filled)
+	return nil
+}
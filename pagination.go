@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Paginação de /atas e /enriched.
+//
+// Dois modos convivem na mesma resposta (PaginatedResponse):
+//   - page/limit: offset clássico, mantido para clientes existentes;
+//   - cursor: token opaco que guarda o ID do item de fronteira (GlobalID ou URL da
+//     ata) e a ordenação usada. A página seguinte começa logo após esse
+//     item na ordenação atual, então recarregar o dataset não desloca as páginas.
+//
+// Em ambos os modos a ordenação termina sempre no ID, o que torna a ordem total e
+// independente da ordem de carga do arquivo. As atas usam a URL porque NumeroReuniao pode
+// faltar (0) em atas com falha no parse, e IDs repetidos quebrariam a busca do cursor.
+
+const (
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+var errCursorInvalid = errors.New("Parâmetro 'cursor' inválido.")
+
+// pageCursor é o conteúdo do token (JSON em base64url). Clientes devem tratá-lo como opaco.
+type pageCursor struct {
+	ID   string `json:"id"`
+	Dir  string `json:"dir"`
+	Sort string `json:"sort"`
+}
+
+func encodeCursor(cur pageCursor) string {
+	raw, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(token string) (pageCursor, error) {
+	var cur pageCursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cur, errCursorInvalid
+	}
+	if err := json.Unmarshal(raw, &cur); err != nil {
+		return cur, errCursorInvalid
+	}
+	if cur.Dir != cursorNext && cur.Dir != cursorPrev {
+		return cur, errCursorInvalid
+	}
+	return cur, nil
+}
+
+// parsePagination lê page e limit (máx 100), aplicando os padrões em valores inválidos.
+func parsePagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return page, limit
+}
+
+// pageQuery descreve como paginar uma lista já filtrada e ordenada por keys.
+type pageQuery[T any] struct {
+	SortSpec string                    // valor de ?sort= (faz parte do cursor)
+	Keys     []sortKey[T]              // ordenação completa, terminando no ID
+	ID       func(T) string            // GlobalID ou URL da ata; único no store
+	Lookup   func(id string) (T, bool) // busca o item de fronteira no store
+}
+
+// buildPage pagina items (ordenados por q.Keys) por cursor, se ?cursor= vier preenchido,
+// ou por page/limit, preenchendo os links next/prev em ambos os casos.
+func buildPage[T any](c *gin.Context, items []T, q pageQuery[T]) (PaginatedResponse[T], error) {
+	page, limit := parsePagination(c)
+	total := len(items)
+
+	// Em modo cursor Page fica 0: a posição é dada pelo cursor, não por offset.
+	var start, end int
+	response := PaginatedResponse[T]{Limit: limit, Total: total, TotalPages: (total + limit - 1) / limit}
+
+	if token := c.Query("cursor"); token != "" {
+		cur, err := decodeCursor(token)
+		if err != nil {
+			return response, err
+		}
+		if cur.Sort != q.SortSpec {
+			return response, fmt.Errorf("Cursor gerado com outra ordenação (sort=%q).", cur.Sort)
+		}
+		// O item de fronteira é buscado no store (não na lista filtrada), então o cursor
+		// continua válido mesmo que o item deixe de passar nos filtros.
+		boundary, ok := q.Lookup(cur.ID)
+		if !ok {
+			return response, errors.New("Cursor expirado: o item de referência não existe mais no dataset.")
+		}
+		compare := compareBy(q.Keys)
+		pos, found := slices.BinarySearchFunc(items, boundary, compare)
+		if cur.Dir == cursorNext {
+			if found {
+				pos++
+			}
+			start, end = pos, min(pos+limit, total)
+		} else {
+			start, end = max(pos-limit, 0), pos
+		}
+	} else {
+		response.Page = page
+		// Páginas além da última ficam vazias; a comparação vem antes da multiplicação
+		// para que um page enorme não estoure (page-1)*limit.
+		start = total
+		if page <= response.TotalPages {
+			start = (page - 1) * limit
+		}
+		end = min(start+limit, total)
+	}
+
+	response.Data = append([]T{}, items[start:end]...)
+	if start > 0 && end > start {
+		response.PrevCursor = encodeCursor(pageCursor{ID: q.ID(items[start]), Dir: cursorPrev, Sort: q.SortSpec})
+		response.Prev = cursorLink(c, response.PrevCursor)
+	}
+	if end < total && end > start {
+		response.NextCursor = encodeCursor(pageCursor{ID: q.ID(items[end-1]), Dir: cursorNext, Sort: q.SortSpec})
+		response.Next = cursorLink(c, response.NextCursor)
+	}
+	return response, nil
+}
+
+// cursorLink monta a URL relativa da requisição atual trocando page por cursor.
+func cursorLink(c *gin.Context, cursor string) string {
+	values := c.Request.URL.Query()
+	values.Del("page")
+	values.Set("cursor", cursor)
+	return c.Request.URL.Path + "?" + values.Encode()
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Writer Parquet mínimo: um row group, uma data page (v1) por coluna, encoding PLAIN,
+// sem compressão e todas as colunas REQUIRED. Suficiente para pandas/pyarrow/duckdb
+// lerem as exportações sem adicionar dependências ao projeto.
+
+// Constantes de parquet.thrift
+const (
+	parquetTypeBoolean   = 0
+	parquetTypeInt64     = 2
+	parquetTypeDouble    = 5
+	parquetTypeByteArray = 6
+
+	parquetRepetitionRequired = 0
+	parquetConvertedUTF8      = 0
+	parquetEncodingPlain      = 0
+	parquetEncodingRLE        = 3
+	parquetCodecUncompressed  = 0
+	parquetPageTypeData       = 0
+)
+
+const parquetMagic = "PAR1"
+
+// Tipos do protocolo Thrift Compact
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter serializa estruturas no protocolo Thrift Compact, usado nos metadados do Parquet.
+type thriftWriter struct {
+	buf       bytes.Buffer
+	lastField []int16
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{lastField: []int16{0}}
+}
+
+func (w *thriftWriter) varint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	w.buf.Write(tmp[:n])
+}
+
+func (w *thriftWriter) zigzag(v int64) {
+	w.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *thriftWriter) fieldHeader(id int16, typ byte) {
+	last := &w.lastField[len(w.lastField)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.zigzag(int64(id))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) i32Field(id int16, v int32) {
+	w.fieldHeader(id, thriftI32)
+	w.zigzag(int64(v))
+}
+
+func (w *thriftWriter) i64Field(id int16, v int64) {
+	w.fieldHeader(id, thriftI64)
+	w.zigzag(v)
+}
+
+func (w *thriftWriter) binary(s string) {
+	w.varint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+func (w *thriftWriter) stringField(id int16, s string) {
+	w.fieldHeader(id, thriftBinary)
+	w.binary(s)
+}
+
+func (w *thriftWriter) listField(id int16, elemType byte, size int) {
+	w.fieldHeader(id, thriftList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		w.buf.WriteByte(0xF0 | elemType)
+		w.varint(uint64(size))
+	}
+}
+
+// structBegin abre um struct aninhado; fieldID < 0 indica elemento de lista (sem cabeçalho de campo).
+func (w *thriftWriter) structBegin(fieldID int16) {
+	if fieldID >= 0 {
+		w.fieldHeader(fieldID, thriftStruct)
+	}
+	w.lastField = append(w.lastField, 0)
+}
+
+func (w *thriftWriter) structEnd() {
+	w.buf.WriteByte(0)
+	w.lastField = w.lastField[:len(w.lastField)-1]
+}
+
+func (w *thriftWriter) Bytes() []byte {
+	return w.buf.Bytes()
+}
+
+// parquetColumnChunk guarda o que o rodapé precisa saber de cada coluna já gravada.
+type parquetColumnChunk struct {
+	physicalType int32
+	name         string
+	offset       int64
+	size         int64
+}
+
+func parquetPhysicalType(kind columnKind) int32 {
+	switch kind {
+	case columnInt:
+		return parquetTypeInt64
+	case columnFloat:
+		return parquetTypeDouble
+	case columnBool:
+		return parquetTypeBoolean
+	default:
+		return parquetTypeByteArray
+	}
+}
+
+// encodeParquetColumn codifica os valores de uma coluna em PLAIN.
+func encodeParquetColumn(t *exportTable, col int) ([]byte, error) {
+	var buf bytes.Buffer
+	kind := t.Columns[col].Kind
+	var bits byte
+	for i, row := range t.Rows {
+		switch kind {
+		case columnInt:
+			v, ok := row[col].(int)
+			if !ok {
+				return nil, fmt.Errorf("coluna %s: valor %v não é inteiro", t.Columns[col].Name, row[col])
+			}
+			binary.Write(&buf, binary.LittleEndian, int64(v))
+		case columnFloat:
+			v, ok := row[col].(float64)
+			if !ok {
+				return nil, fmt.Errorf("coluna %s: valor %v não é float", t.Columns[col].Name, row[col])
+			}
+			binary.Write(&buf, binary.LittleEndian, math.Float64bits(v))
+		case columnBool:
+			v, ok := row[col].(bool)
+			if !ok {
+				return nil, fmt.Errorf("coluna %s: valor %v não é booleano", t.Columns[col].Name, row[col])
+			}
+			// Booleanos PLAIN são bit-packed, bit menos significativo primeiro
+			if v {
+				bits |= 1 << (i % 8)
+			}
+			if i%8 == 7 {
+				buf.WriteByte(bits)
+				bits = 0
+			}
+		default:
+			s := fmt.Sprint(row[col])
+			binary.Write(&buf, binary.LittleEndian, uint32(len(s)))
+			buf.WriteString(s)
+		}
+	}
+	if kind == columnBool && len(t.Rows)%8 != 0 {
+		buf.WriteByte(bits)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeParquet(out io.Writer, t *exportTable) error {
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+
+	numRows := int64(len(t.Rows))
+	chunks := make([]parquetColumnChunk, 0, len(t.Columns))
+	for col, c := range t.Columns {
+		data, err := encodeParquetColumn(t, col)
+		if err != nil {
+			return err
+		}
+
+		header := newThriftWriter()
+		header.i32Field(1, parquetPageTypeData)
+		header.i32Field(2, int32(len(data)))
+		header.i32Field(3, int32(len(data)))
+		header.structBegin(5)
+		header.i32Field(1, int32(numRows))
+		header.i32Field(2, parquetEncodingPlain)
+		header.i32Field(3, parquetEncodingRLE)
+		header.i32Field(4, parquetEncodingRLE)
+		header.structEnd()
+		header.buf.WriteByte(0)
+
+		chunk := parquetColumnChunk{
+			physicalType: parquetPhysicalType(c.Kind),
+			name:         c.Name,
+			offset:       int64(file.Len()),
+			size:         int64(len(header.Bytes()) + len(data)),
+		}
+		file.Write(header.Bytes())
+		file.Write(data)
+		chunks = append(chunks, chunk)
+	}
+
+	meta := newThriftWriter()
+	meta.i32Field(1, 1) // version
+
+	meta.listField(2, thriftStruct, len(t.Columns)+1)
+	meta.structBegin(-1)
+	meta.stringField(4, "schema")
+	meta.i32Field(5, int32(len(t.Columns)))
+	meta.structEnd()
+	for _, c := range t.Columns {
+		meta.structBegin(-1)
+		meta.i32Field(1, parquetPhysicalType(c.Kind))
+		meta.i32Field(3, parquetRepetitionRequired)
+		meta.stringField(4, c.Name)
+		if c.Kind == columnString {
+			meta.i32Field(6, parquetConvertedUTF8)
+		}
+		meta.structEnd()
+	}
+
+	meta.i64Field(3, numRows)
+
+	var totalSize int64
+	for _, chunk := range chunks {
+		totalSize += chunk.size
+	}
+	meta.listField(4, thriftStruct, 1)
+	meta.structBegin(-1)
+	meta.listField(1, thriftStruct, len(chunks))
+	for _, chunk := range chunks {
+		meta.structBegin(-1)
+		meta.i64Field(2, chunk.offset)
+		meta.structBegin(3)
+		meta.i32Field(1, chunk.physicalType)
+		meta.listField(2, thriftI32, 1)
+		meta.zigzag(parquetEncodingPlain)
+		meta.listField(3, thriftBinary, 1)
+		meta.binary(chunk.name)
+		meta.i32Field(4, parquetCodecUncompressed)
+		meta.i64Field(5, numRows)
+		meta.i64Field(6, chunk.size)
+		meta.i64Field(7, chunk.size)
+		meta.i64Field(9, chunk.offset)
+		meta.structEnd()
+		meta.structEnd()
+	}
+	meta.i64Field(2, totalSize)
+	meta.i64Field(3, numRows)
+	meta.structEnd()
+
+	meta.stringField(6, "copom-crawler")
+	meta.buf.WriteByte(0)
+
+	file.Write(meta.Bytes())
+	binary.Write(&file, binary.LittleEndian, uint32(len(meta.Bytes())))
+	file.WriteString(parquetMagic)
+
+	_, err := out.Write(file.Bytes())
+	return err
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// thriftReader decodifica o protocolo Thrift Compact de forma genérica: structs viram
+// map[id do campo]valor, listas viram []any, inteiros viram int64 e binários, string.
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) byte() byte {
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) varint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		panic(fmt.Sprintf("varint inválido na posição %d", r.pos))
+	}
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(typ byte) any {
+	switch typ {
+	case 1, 2: // bool em elemento de lista
+		return r.byte() == 1
+	case 3:
+		return int64(int8(r.byte()))
+	case 4, thriftI32, thriftI64:
+		return r.zigzag()
+	case 7:
+		v := math.Float64frombits(binary.LittleEndian.Uint64(r.data[r.pos:]))
+		r.pos += 8
+		return v
+	case thriftBinary:
+		n := int(r.varint())
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case thriftList:
+		header := r.byte()
+		size, elemType := int(header>>4), header&0x0F
+		if size == 15 {
+			size = int(r.varint())
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i] = r.value(elemType)
+		}
+		return list
+	case thriftStruct:
+		return r.structure()
+	}
+	panic(fmt.Sprintf("tipo Thrift não suportado: %d", typ))
+}
+
+func (r *thriftReader) structure() map[int16]any {
+	fields := make(map[int16]any)
+	var last int16
+	for {
+		header := r.byte()
+		if header == 0 {
+			return fields
+		}
+		typ := header & 0x0F
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		last = id
+		switch typ {
+		case 1:
+			fields[id] = true
+		case 2:
+			fields[id] = false
+		default:
+			fields[id] = r.value(typ)
+		}
+	}
+}
+
+func parquetTestTable() *exportTable {
+	t := &exportTable{
+		Name: "teste",
+		Columns: []exportColumn{
+			{Name: "numero", Kind: columnInt},
+			{Name: "taxa", Kind: columnFloat},
+			{Name: "alta", Kind: columnBool},
+			{Name: "texto", Kind: columnString},
+		},
+	}
+	// 11 linhas para os booleanos ocuparem mais de um byte
+	for i := range 11 {
+		t.Rows = append(t.Rows, []any{i - 3, float64(i) * 0.25, i%3 == 0, fmt.Sprintf("Inflação %d", i)})
+	}
+	return t
+}
+
+// decodeParquetColumn lê os valores PLAIN de uma data page.
+func decodeParquetColumn(t *testing.T, data []byte, physicalType int64, rows int) []any {
+	t.Helper()
+	values := make([]any, 0, rows)
+	pos := 0
+	for i := range rows {
+		switch physicalType {
+		case parquetTypeInt64:
+			values = append(values, int(int64(binary.LittleEndian.Uint64(data[pos:]))))
+			pos += 8
+		case parquetTypeDouble:
+			values = append(values, math.Float64frombits(binary.LittleEndian.Uint64(data[pos:])))
+			pos += 8
+		case parquetTypeBoolean:
+			values = append(values, data[i/8]&(1<<(i%8)) != 0)
+		case parquetTypeByteArray:
+			n := int(binary.LittleEndian.Uint32(data[pos:]))
+			values = append(values, string(data[pos+4:pos+4+n]))
+			pos += 4 + n
+		default:
+			t.Fatalf("tipo físico inesperado: %d", physicalType)
+		}
+	}
+	if physicalType == parquetTypeBoolean {
+		pos = (rows + 7) / 8
+	}
+	if pos != len(data) {
+		t.Fatalf("página com %d bytes, valores ocupam %d", len(data), pos)
+	}
+	return values
+}
+
+func TestWriteParquetRoundTrip(t *testing.T) {
+	table := parquetTestTable()
+	var out bytes.Buffer
+	if err := writeParquet(&out, table); err != nil {
+		t.Fatal(err)
+	}
+	file := out.Bytes()
+
+	if string(file[:4]) != parquetMagic || string(file[len(file)-4:]) != parquetMagic {
+		t.Fatal("arquivo sem a assinatura PAR1 no início e no fim")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footerStart := len(file) - 8 - footerLen
+	footer := &thriftReader{data: file[footerStart : len(file)-8]}
+	meta := footer.structure()
+	if footer.pos != footerLen {
+		t.Fatalf("rodapé decodificado com %d bytes, declarado com %d", footer.pos, footerLen)
+	}
+
+	if meta[3] != int64(len(table.Rows)) {
+		t.Fatalf("num_rows = %v, esperado %d", meta[3], len(table.Rows))
+	}
+	schema := meta[2].([]any)
+	if len(schema) != len(table.Columns)+1 {
+		t.Fatalf("schema com %d elementos, esperado %d", len(schema), len(table.Columns)+1)
+	}
+	if root := schema[0].(map[int16]any); root[5] != int64(len(table.Columns)) {
+		t.Fatalf("raiz do schema com num_children = %v", root[5])
+	}
+
+	rowGroups := meta[4].([]any)
+	if len(rowGroups) != 1 {
+		t.Fatalf("%d row groups, esperado 1", len(rowGroups))
+	}
+	rowGroup := rowGroups[0].(map[int16]any)
+	if rowGroup[3] != int64(len(table.Rows)) {
+		t.Fatalf("row group com num_rows = %v", rowGroup[3])
+	}
+	chunks := rowGroup[1].([]any)
+	if len(chunks) != len(table.Columns) {
+		t.Fatalf("%d column chunks, esperado %d", len(chunks), len(table.Columns))
+	}
+
+	end := 4 // logo após o PAR1 inicial
+	for col, c := range table.Columns {
+		element := schema[col+1].(map[int16]any)
+		if element[4] != c.Name || element[1] != int64(parquetPhysicalType(c.Kind)) {
+			t.Fatalf("coluna %d do schema: nome %v, tipo %v", col, element[4], element[1])
+		}
+
+		chunkMeta := chunks[col].(map[int16]any)[3].(map[int16]any)
+		if path := chunkMeta[3].([]any); len(path) != 1 || path[0] != c.Name {
+			t.Fatalf("coluna %s: path_in_schema = %v", c.Name, path)
+		}
+		offset, size := int(chunkMeta[9].(int64)), int(chunkMeta[6].(int64))
+		if offset != end {
+			t.Fatalf("coluna %s começa em %d, esperado %d", c.Name, offset, end)
+		}
+		end = offset + size
+
+		page := &thriftReader{data: file[offset:end]}
+		header := page.structure()
+		dataHeader := header[5].(map[int16]any)
+		if header[1] != int64(parquetPageTypeData) || dataHeader[1] != int64(len(table.Rows)) {
+			t.Fatalf("coluna %s: cabeçalho de página inesperado %v", c.Name, header)
+		}
+		data := file[offset+page.pos : end]
+		if header[2] != int64(len(data)) {
+			t.Fatalf("coluna %s: página declara %v bytes, tem %d", c.Name, header[2], len(data))
+		}
+
+		values := decodeParquetColumn(t, data, chunkMeta[1].(int64), len(table.Rows))
+		for i, row := range table.Rows {
+			if values[i] != row[col] {
+				t.Fatalf("coluna %s, linha %d: lido %v, gravado %v", c.Name, i, values[i], row[col])
+			}
+		}
+	}
+	if end != footerStart {
+		t.Fatalf("column chunks terminam em %d, rodapé começa em %d", end, footerStart)
+	}
+}
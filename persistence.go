@@ -0,0 +1,390 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Quantidade padrão de versões anteriores mantidas (arquivo.bak.1 é a mais recente).
+// Pode ser alterada pela variável de ambiente DATASET_BACKUPS (0 desativa).
+const defaultDatasetBackups = 3
+
+var (
+	// ErrDatasetNotFound indica que o arquivo ainda não existe (primeira execução).
+	ErrDatasetNotFound = errors.New("dataset não encontrado")
+	// ErrDatasetCorrupted indica que o conteúdo não confere com o checksum salvo.
+	ErrDatasetCorrupted = errors.New("dataset corrompido (checksum não confere)")
+	// ErrSchemaOutdated indica que o arquivo precisa passar por -mode=migrate antes de ser usado.
+	ErrSchemaOutdated = errors.New("schema do dataset desatualizado")
+	// ErrSchemaTooNew indica um arquivo gravado por uma versão mais nova do crawler.
+	ErrSchemaTooNew = errors.New("schema do dataset mais novo que o suportado")
+)
+
+// datasetEnvelope é o formato em disco a partir do schema 1. Arquivos antigos, com o array
+// JSON direto na raiz, são tratados como schema 0.
+type datasetEnvelope struct {
+	SchemaVersion int             `json:"schema_version"`
+	Data          json.RawMessage `json:"data"`
+}
+
+func checksumFilename(filename string) string {
+	return filename + ".sha256"
+}
+
+func backupFilename(filename string, n int) string {
+	return fmt.Sprintf("%s.bak.%d", filename, n)
+}
+
+func datasetBackups() int {
+	if val, err := strconv.Atoi(os.Getenv("DATASET_BACKUPS")); err == nil && val >= 0 {
+		return val
+	}
+	return defaultDatasetBackups
+}
+
+// --- Persistência ---
+
+// LoadAtas carrega o dataset de atas exigindo o schema atual.
+func LoadAtas(filename string) ([]CopomAta, error) {
+	atas, version, err := readAtasFile(filename)
+	if err == nil {
+		err = checkSchemaVersion(filename, version)
+	}
+	if err != nil {
+		return []CopomAta{}, err
+	}
+	return atas, nil
+}
+
+func SaveAtas(filename string, atas []CopomAta) error {
+	return saveJSONDataset(filename, atas)
+}
+
+// LoadEnrichedData carrega o dataset enriquecido exigindo o schema atual.
+func LoadEnrichedData(filename string) ([]EnrichedParagraph, error) {
+	data, version, err := readEnrichedFile(filename)
+	if err == nil {
+		err = checkSchemaVersion(filename, version)
+	}
+	if err != nil {
+		return []EnrichedParagraph{}, err
+	}
+	return data, nil
+}
+
+func SaveEnrichedData(filename string, data []EnrichedParagraph) error {
+	return saveJSONDataset(filename, data)
+}
+
+// readAtasFile lê o dataset de atas em qualquer versão do schema, retornando a versão encontrada.
+// Usado pelo migrate e pelo servidor (somente leitura); quem grava deve usar LoadAtas.
+func readAtasFile(filename string) ([]CopomAta, int, error) {
+	var atas []CopomAta
+	version, err := loadJSONDataset(filename, &atas)
+	if err != nil {
+		return []CopomAta{}, 0, err
+	}
+	return atas, version, nil
+}
+
+// readEnrichedFile é o equivalente de readAtasFile para dataset_enriched.json.
+func readEnrichedFile(filename string) ([]EnrichedParagraph, int, error) {
+	var data []EnrichedParagraph
+	version, err := loadJSONDataset(filename, &data)
+	if err != nil {
+		return []EnrichedParagraph{}, 0, err
+	}
+	return data, version, nil
+}
+
+// readAtasReadOnly carrega o dataset de atas para uso somente leitura (servidor, exportação):
+// schemas antigos são aceitos, apenas com o aviso de que é preciso migrar.
+func readAtasReadOnly(filename string) ([]CopomAta, error) {
+	atas, version, err := readAtasFile(filename)
+	if err != nil {
+		return atas, err
+	}
+	if err := checkSchemaVersion(filename, version); err != nil {
+		log.Printf("AVISO: %v. Execute -mode=migrate.", err)
+	}
+	return atas, nil
+}
+
+// readEnrichedReadOnly é o equivalente de readAtasReadOnly para dataset_enriched.json.
+func readEnrichedReadOnly(filename string) ([]EnrichedParagraph, error) {
+	data, version, err := readEnrichedFile(filename)
+	if err != nil {
+		return data, err
+	}
+	if err := checkSchemaVersion(filename, version); err != nil {
+		log.Printf("AVISO: %v. Execute -mode=migrate.", err)
+	}
+	return data, nil
+}
+
+func checkSchemaVersion(filename string, version int) error {
+	switch {
+	case version < currentSchemaVersion:
+		return fmt.Errorf("%w: %s está no schema %d, esperado %d", ErrSchemaOutdated, filename, version, currentSchemaVersion)
+	case version > currentSchemaVersion:
+		return fmt.Errorf("%w: %s está no schema %d, suportado até %d", ErrSchemaTooNew, filename, version, currentSchemaVersion)
+	}
+	return nil
+}
+
+// datasetLoadHint sugere ao usuário o que fazer quando um dataset não pode ser carregado.
+func datasetLoadHint(filename string, err error) string {
+	if errors.Is(err, ErrSchemaOutdated) {
+		return "Execute -mode=migrate antes de continuar."
+	}
+	return fmt.Sprintf("Restaure a última versão válida (%s) antes de continuar.", backupFilename(filename, 1))
+}
+
+// loadJSONDataset decodifica o arquivo em v e retorna a versão do schema gravada nele.
+func loadJSONDataset(filename string, v any) (int, error) {
+	data, err := readDatasetFile(filename)
+	if err != nil {
+		return 0, err
+	}
+
+	// Schema 0: array JSON direto na raiz
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, v); err != nil {
+			return 0, fmt.Errorf("falha ao decodificar %s: %w", filename, err)
+		}
+		return 0, nil
+	}
+
+	var envelope datasetEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return 0, fmt.Errorf("falha ao decodificar %s: %w", filename, err)
+	}
+	if len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, v); err != nil {
+			return 0, fmt.Errorf("falha ao decodificar %s: %w", filename, err)
+		}
+	}
+	return envelope.SchemaVersion, nil
+}
+
+// saveJSONDataset grava v no envelope com a versão atual do schema.
+func saveJSONDataset(filename string, v any) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+	envelope := struct {
+		SchemaVersion int `json:"schema_version"`
+		Data          any `json:"data"`
+	}{currentSchemaVersion, v}
+	if err := encoder.Encode(envelope); err != nil {
+		return err
+	}
+	return writeDatasetFile(filename, buf.Bytes())
+}
+
+// readDatasetFile lê o arquivo inteiro e, se houver checksum sidecar, verifica a integridade.
+// Arquivos antigos, gravados antes do sidecar existir, são aceitos sem verificação. O sidecar
+// pode listar dois checksums (o novo e o da versão anterior, ver writeDatasetFile); o arquivo
+// é válido se conferir com qualquer um deles.
+func readDatasetFile(filename string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrDatasetNotFound, filename)
+		}
+		return nil, fmt.Errorf("falha ao ler %s: %w", filename, err)
+	}
+
+	sidecar, err := os.ReadFile(checksumFilename(filename))
+	if errors.Is(err, fs.ErrNotExist) {
+		return data, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler checksum de %s: %w", filename, err)
+	}
+
+	sums := sidecarChecksums(sidecar)
+	if len(sums) == 0 {
+		return nil, fmt.Errorf("%w: %s (checksum vazio)", ErrDatasetCorrupted, filename)
+	}
+	if got := checksumOf(data); !slices.Contains(sums, got) {
+		return nil, fmt.Errorf("%w: %s (esperado %s, obtido %s)", ErrDatasetCorrupted, filename, sums[0], got)
+	}
+	return data, nil
+}
+
+// sidecarChecksums retorna o primeiro campo de cada linha do sidecar ("<sha256>  <arquivo>").
+func sidecarChecksums(sidecar []byte) []string {
+	var sums []string
+	for _, line := range strings.Split(string(sidecar), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			sums = append(sums, fields[0])
+		}
+	}
+	return sums
+}
+
+// writeDatasetFile grava de forma atômica: arquivo temporário + fsync + rename.
+// Antes de substituir, a versão atual é preservada em arquivo.bak.1 (rotacionando as anteriores).
+// O sidecar é trocado antes do arquivo e lista o checksum novo e o da versão atual, então uma
+// queda entre os dois renames deixa um par válido (dados antigos ou novos, ambos conferem).
+// Em qualquer ponto de falha, o arquivo original permanece íntegro.
+func writeDatasetFile(filename string, data []byte) error {
+	sidecar := checksumOf(data) + "  " + filepath.Base(filename) + "\n"
+	if previous, err := currentChecksum(filename); err != nil {
+		return err
+	} else if previous != "" {
+		sidecar += previous + "  " + filepath.Base(filename) + "\n"
+	}
+
+	dataTmp, err := writeTempFile(filename, data)
+	if err != nil {
+		return err
+	}
+	sumTmp, err := writeTempFile(checksumFilename(filename), []byte(sidecar))
+	if err != nil {
+		os.Remove(dataTmp)
+		return err
+	}
+
+	if err := rotateBackups(filename, datasetBackups()); err != nil {
+		// Backup é uma proteção extra: não impede a gravação
+		log.Printf("AVISO: Falha ao rotacionar backups de %s: %v", filename, err)
+	}
+
+	if err := os.Rename(sumTmp, checksumFilename(filename)); err != nil {
+		os.Remove(dataTmp)
+		os.Remove(sumTmp)
+		return fmt.Errorf("falha ao gravar checksum de %s: %w", filename, err)
+	}
+	if err := os.Rename(dataTmp, filename); err != nil {
+		os.Remove(dataTmp)
+		return fmt.Errorf("falha ao substituir %s: %w", filename, err)
+	}
+	return syncDir(filepath.Dir(filename))
+}
+
+// currentChecksum é o checksum da versão em disco ("" se o arquivo ainda não existe).
+func currentChecksum(filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("falha ao ler %s: %w", filename, err)
+	}
+	return checksumOf(data), nil
+}
+
+func writeTempFile(filename string, data []byte) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// rotateBackups desloca arquivo.bak.N-1 -> arquivo.bak.N e preserva a versão atual em arquivo.bak.1.
+// O checksum sidecar acompanha cada backup (arquivo.bak.N.sha256), para que LoadAtas/LoadEnrichedData
+// também verifiquem a integridade ao ler um backup diretamente.
+func rotateBackups(filename string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	if _, err := os.Stat(filename); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	for _, name := range []func(n int) string{
+		func(n int) string { return backupFilename(filename, n) },
+		func(n int) string { return checksumFilename(backupFilename(filename, n)) },
+	} {
+		os.Remove(name(keep))
+		for i := keep - 1; i >= 1; i-- {
+			if err := os.Rename(name(i), name(i+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+	}
+
+	// Hard link evita copiar o arquivo e garante que o original nunca deixa de existir
+	for src, dst := range map[string]string{
+		filename:                   backupFilename(filename, 1),
+		checksumFilename(filename): checksumFilename(backupFilename(filename, 1)),
+	} {
+		if _, err := os.Stat(src); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err := os.Link(src, dst); err != nil {
+			if err := copyFile(src, dst); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// syncDir garante que os renames fiquem registrados no diretório (necessário em ext4/xfs).
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil && !errors.Is(err, os.ErrInvalid) {
+		return err
+	}
+	return nil
+}
+
+func checksumOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
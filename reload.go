@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Intervalo padrão de verificação dos arquivos de dataset pelo servidor.
+// Pode ser alterado pela variável de ambiente RELOAD_INTERVAL (ex: "30s"; "0" desativa).
+const defaultReloadInterval = 10 * time.Second
+
+func reloadInterval() time.Duration {
+	val := os.Getenv("RELOAD_INTERVAL")
+	if val == "" {
+		return defaultReloadInterval
+	}
+	if val == "0" {
+		return 0
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d < 0 {
+		log.Printf("AVISO: RELOAD_INTERVAL inválido (%q). Usando %v.", val, defaultReloadInterval)
+		return defaultReloadInterval
+	}
+	return d
+}
+
+// fileState identifica uma versão do arquivo em disco. O checksum sidecar muda a cada
+// gravação, mesmo quando o mtime não tem resolução suficiente.
+type fileState struct {
+	ModTime  time.Time
+	Size     int64
+	Checksum string
+}
+
+func statDatasetFile(filename string) (fileState, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return fileState{}, err
+	}
+	state := fileState{ModTime: info.ModTime(), Size: info.Size()}
+	if sum, err := os.ReadFile(checksumFilename(filename)); err == nil {
+		state.Checksum = string(sum)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fileState{}, err
+	}
+	return state, nil
+}
+
+// ReloadResult resume uma recarga dos datasets no servidor.
+type ReloadResult struct {
+	Atas       int       `json:"atas"`
+	Paragraphs int       `json:"paragraphs"`
+	ReloadedAt time.Time `json:"reloaded_at"`
+}
+
+// datasetReloader recarrega dataset_raw.json e dataset_enriched.json nos stores do servidor,
+// seja pelo watcher (polling de mtime/tamanho/checksum) ou sob demanda (POST /admin/reload).
+type datasetReloader struct {
+	mu               sync.Mutex // serializa recargas concorrentes
+	atas             *ataStore
+	enriched         *enrichedStore
+	rawFilename      string
+	enrichedFilename string
+	rawState         fileState
+	enrichedState    fileState
+	lastResult       ReloadResult
+	onReload         []func()
+}
+
+func newDatasetReloader(atas *ataStore, enriched *enrichedStore, rawFilename, enrichedFilename string) *datasetReloader {
+	return &datasetReloader{
+		atas:             atas,
+		enriched:         enriched,
+		rawFilename:      rawFilename,
+		enrichedFilename: enrichedFilename,
+	}
+}
+
+// OnReload registra uma função chamada após cada recarga bem-sucedida (ex: reconstruir
+// índices derivados dos stores). Deve ser chamada antes do primeiro Reload.
+func (r *datasetReloader) OnReload(fn func()) {
+	r.onReload = append(r.onReload, fn)
+}
+
+// Reload lê os dois arquivos e troca o conteúdo dos stores. Se um arquivo não puder ser lido
+// (ex: gravação em andamento, checksum divergente), os dados atuais daquele store são mantidos.
+func (r *datasetReloader) Reload() (ReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reload(true, true)
+}
+
+func (r *datasetReloader) reload(raw, enriched bool) (ReloadResult, error) {
+	var errs []error
+	replaced := false
+
+	if raw {
+		state, _ := statDatasetFile(r.rawFilename)
+		atas, err := readAtasReadOnly(r.rawFilename)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.rawFilename, err))
+		} else {
+			r.atas.replace(atas)
+			r.rawState = state
+			replaced = true
+		}
+	}
+
+	if enriched {
+		state, _ := statDatasetFile(r.enrichedFilename)
+		data, err := readEnrichedReadOnly(r.enrichedFilename)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.enrichedFilename, err))
+		} else {
+			r.enriched.replace(data)
+			r.enrichedState = state
+			replaced = true
+		}
+	}
+
+	if replaced {
+		for _, fn := range r.onReload {
+			fn()
+		}
+	}
+
+	r.lastResult.Atas = len(r.atas.all())
+	r.lastResult.Paragraphs = len(r.enriched.all())
+	r.lastResult.ReloadedAt = time.Now()
+	return r.lastResult, errors.Join(errs...)
+}
+
+// reloadIfChanged recarrega apenas os arquivos cujo estado em disco mudou desde a última carga.
+func (r *datasetReloader) reloadIfChanged() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rawState, rawErr := statDatasetFile(r.rawFilename)
+	enrichedState, enrichedErr := statDatasetFile(r.enrichedFilename)
+	rawChanged := rawErr == nil && rawState != r.rawState
+	enrichedChanged := enrichedErr == nil && enrichedState != r.enrichedState
+	if !rawChanged && !enrichedChanged {
+		return
+	}
+
+	result, err := r.reload(rawChanged, enrichedChanged)
+	if err != nil {
+		log.Printf("AVISO: Falha ao recarregar datasets (nova tentativa em seguida): %v", err)
+		return
+	}
+	log.Printf("Datasets recarregados: %d atas, %d parágrafos enriquecidos.", result.Atas, result.Paragraphs)
+}
+
+// Watch verifica periodicamente os arquivos em uma goroutine própria.
+func (r *datasetReloader) Watch(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			r.reloadIfChanged()
+		}
+	}()
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Agendador do modo 'daemon': a partir do calendário de reuniões do Copom, dispara o scrape
+// logo após a publicação esperada de cada ata, repete até a ata aparecer e então dispara o
+// enriquecimento, também repetido em caso de falha. Os disparos passam pelo jobRegistry,
+// então aparecem em /admin/jobs e não concorrem com jobs manuais.
+
+const defaultCalendarFilename = "copom_calendar.json"
+
+const (
+	defaultPublicationBusinessDays = 6
+	defaultPublicationTime         = "08:00"
+	defaultCalendarTimezone        = "America/Sao_Paulo"
+	defaultSchedulerRetry          = time.Hour
+	defaultSchedulerGiveUp         = 7 * 24 * time.Hour
+)
+
+// meetingCalendar é o conteúdo de copom_calendar.json.
+type meetingCalendar struct {
+	// Meetings são as datas de término das reuniões (YYYY-MM-DD), igual a data_reuniao.
+	Meetings []string `json:"meetings"`
+	// Holidays são feriados que não contam como dia útil (YYYY-MM-DD).
+	Holidays []string `json:"holidays"`
+	// A ata é esperada PublicationBusinessDays dias úteis após a reunião, no horário
+	// PublicationTime (HH:MM) do fuso Timezone.
+	PublicationBusinessDays int    `json:"publication_business_days"`
+	PublicationTime         string `json:"publication_time"`
+	Timezone                string `json:"timezone"`
+
+	location *time.Location
+	holidays map[string]bool
+}
+
+// calendarFilename permite trocar o arquivo pela variável COPOM_CALENDAR.
+func calendarFilename() string {
+	if name := os.Getenv("COPOM_CALENDAR"); name != "" {
+		return name
+	}
+	return defaultCalendarFilename
+}
+
+func loadMeetingCalendar(filename string) (*meetingCalendar, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var cal meetingCalendar
+	if err := json.Unmarshal(data, &cal); err != nil {
+		return nil, fmt.Errorf("JSON inválido: %w", err)
+	}
+
+	if cal.PublicationBusinessDays <= 0 {
+		cal.PublicationBusinessDays = defaultPublicationBusinessDays
+	}
+	if cal.PublicationTime == "" {
+		cal.PublicationTime = defaultPublicationTime
+	}
+	if _, err := time.Parse("15:04", cal.PublicationTime); err != nil {
+		return nil, fmt.Errorf("publication_time inválido (%q): use HH:MM", cal.PublicationTime)
+	}
+	if cal.Timezone == "" {
+		cal.Timezone = defaultCalendarTimezone
+	}
+	if cal.location, err = time.LoadLocation(cal.Timezone); err != nil {
+		// Sem tzdata no sistema: Brasília não tem horário de verão desde 2019
+		log.Printf("AVISO: fuso %s indisponível (%v). Usando UTC-3.", cal.Timezone, err)
+		cal.location = time.FixedZone("UTC-3", -3*60*60)
+	}
+
+	cal.holidays = make(map[string]bool, len(cal.Holidays))
+	for _, d := range cal.Holidays {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return nil, fmt.Errorf("feriado inválido (%q): use YYYY-MM-DD", d)
+		}
+		cal.holidays[d] = true
+	}
+	for _, d := range cal.Meetings {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return nil, fmt.Errorf("data de reunião inválida (%q): use YYYY-MM-DD", d)
+		}
+	}
+	slices.Sort(cal.Meetings)
+	cal.Meetings = slices.Compact(cal.Meetings)
+	return &cal, nil
+}
+
+func (cal *meetingCalendar) isBusinessDay(d time.Time) bool {
+	if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+		return false
+	}
+	return !cal.holidays[d.Format("2006-01-02")]
+}
+
+// publicationTime é o momento esperado da publicação da ata da reunião meetingDate.
+func (cal *meetingCalendar) publicationTime(meetingDate string) time.Time {
+	d, _ := time.ParseInLocation("2006-01-02", meetingDate, cal.location)
+	for n := 0; n < cal.PublicationBusinessDays; {
+		d = d.AddDate(0, 0, 1)
+		if cal.isBusinessDay(d) {
+			n++
+		}
+	}
+	hm, _ := time.Parse("15:04", cal.PublicationTime)
+	return time.Date(d.Year(), d.Month(), d.Day(), hm.Hour(), hm.Minute(), 0, 0, cal.location)
+}
+
+// ScheduleStatus é o corpo de GET /admin/schedule.
+type ScheduleStatus struct {
+	Calendar            string     `json:"calendar"`
+	NextMeeting         string     `json:"next_meeting,omitempty"`
+	ExpectedPublication *time.Time `json:"expected_publication,omitempty"`
+	NextAttempt         *time.Time `json:"next_attempt,omitempty"`
+	Attempts            int        `json:"attempts"`
+	LastJob             string     `json:"last_job,omitempty"`
+	LastMessage         string     `json:"last_message,omitempty"`
+}
+
+type scheduler struct {
+	calendar *meetingCalendar
+	store    *ataStore
+	jobs     *jobRegistry
+	retry    time.Duration // intervalo entre tentativas de scrape ou enrich
+	giveUp   time.Duration // desiste após este tempo desde a publicação esperada
+
+	mu     sync.Mutex
+	status ScheduleStatus
+}
+
+func newScheduler(calendar *meetingCalendar, filename string, store *ataStore, jobs *jobRegistry) *scheduler {
+	return &scheduler{
+		calendar: calendar,
+		store:    store,
+		jobs:     jobs,
+		retry:    durationFromEnv("SCHEDULER_RETRY_INTERVAL", defaultSchedulerRetry),
+		giveUp:   durationFromEnv("SCHEDULER_GIVE_UP", defaultSchedulerGiveUp),
+		status:   ScheduleStatus{Calendar: filename},
+	}
+}
+
+// durationFromEnv lê uma duração (ex: "30m") da variável name, com valor padrão.
+func durationFromEnv(name string, def time.Duration) time.Duration {
+	val := os.Getenv(name)
+	if val == "" {
+		return def
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		log.Printf("AVISO: %s inválido (%q). Usando %v.", name, val, def)
+		return def
+	}
+	return d
+}
+
+func (s *scheduler) Status() ScheduleStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+func (s *scheduler) setStatus(fn func(st *ScheduleStatus)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.status)
+}
+
+func (s *scheduler) hasAtaFor(meetingDate string) bool {
+	for _, ata := range s.store.all() {
+		if ata.DataReuniao == meetingDate && ata.Conteudo != "" && !ata.FalhaNoParse {
+			return true
+		}
+	}
+	return false
+}
+
+// nextMeeting retorna a primeira reunião do calendário cuja ata ainda não está no dataset e
+// cuja janela de tentativas (publicação esperada + giveUp) não terminou.
+func (s *scheduler) nextMeeting(now time.Time) (string, time.Time, bool) {
+	for _, meeting := range s.calendar.Meetings {
+		published := s.calendar.publicationTime(meeting)
+		if now.After(published.Add(s.giveUp)) || s.hasAtaFor(meeting) {
+			continue
+		}
+		return meeting, published, true
+	}
+	return "", time.Time{}, false
+}
+
+// Run agenda as reuniões em sequência até ctx ser cancelado ou o calendário acabar.
+func (s *scheduler) Run(ctx context.Context) {
+	for {
+		meeting, published, ok := s.nextMeeting(time.Now())
+		if !ok {
+			log.Printf("[agendador] Nenhuma reunião futura em %s. Atualize o calendário e reinicie o daemon.", s.status.Calendar)
+			s.setStatus(func(st *ScheduleStatus) {
+				st.NextMeeting, st.ExpectedPublication, st.NextAttempt = "", nil, nil
+				st.LastMessage = "calendário sem reuniões pendentes"
+			})
+			return
+		}
+		s.setStatus(func(st *ScheduleStatus) {
+			st.NextMeeting, st.ExpectedPublication, st.NextAttempt = meeting, &published, &published
+			st.Attempts = 0
+		})
+		log.Printf("[agendador] Próxima ata: reunião de %s, publicação esperada em %s.", meeting, published.Format(time.RFC3339))
+
+		if err := sleepContext(ctx, time.Until(published)); err != nil {
+			return
+		}
+		if err := s.collect(ctx, meeting, published.Add(s.giveUp)); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("[agendador] Reunião de %s: %v", meeting, err)
+			s.setStatus(func(st *ScheduleStatus) { st.LastMessage = err.Error() })
+		}
+	}
+}
+
+// collect roda o scrape até a ata da reunião aparecer e depois o enrich, repetindo o que
+// falhar a cada s.retry até deadline.
+func (s *scheduler) collect(ctx context.Context, meeting string, deadline time.Time) error {
+	scraped := false
+	for attempt := 1; ; attempt++ {
+		s.setStatus(func(st *ScheduleStatus) { st.Attempts = attempt })
+
+		if !scraped {
+			log.Printf("[agendador] Tentativa %d de obter a ata da reunião de %s.", attempt, meeting)
+			job, err := s.runJob(ctx, JobScrape)
+			if err != nil && ctx.Err() != nil {
+				return err
+			}
+			if err != nil {
+				log.Printf("[agendador] Scrape não executado: %v", err)
+			} else if s.hasAtaFor(meeting) {
+				log.Printf("[agendador] Ata da reunião de %s obtida (job %s). Iniciando enrichment.", meeting, job.ID)
+				scraped = true
+			}
+		} else {
+			log.Printf("[agendador] Tentativa %d de enriquecer a ata da reunião de %s.", attempt, meeting)
+		}
+
+		if scraped {
+			enrichJob, err := s.runJob(ctx, JobEnrich)
+			if err == nil {
+				s.setStatus(func(st *ScheduleStatus) {
+					st.LastMessage = fmt.Sprintf("ata da reunião de %s obtida e enriquecida (job %s)", meeting, enrichJob.ID)
+				})
+				return nil
+			}
+			if ctx.Err() != nil {
+				return err
+			}
+			log.Printf("[agendador] Enrichment da reunião de %s falhou: %v", meeting, err)
+		}
+
+		next := time.Now().Add(s.retry)
+		if next.After(deadline) {
+			if scraped {
+				return fmt.Errorf("ata obtida, mas o enrichment não foi concluído até %s; desistindo", deadline.Format(time.RFC3339))
+			}
+			return fmt.Errorf("ata não apareceu até %s; desistindo", deadline.Format(time.RFC3339))
+		}
+		message := fmt.Sprintf("ata da reunião de %s ainda não publicada", meeting)
+		if scraped {
+			message = fmt.Sprintf("ata da reunião de %s obtida, mas o enrichment falhou; nova tentativa agendada", meeting)
+		}
+		s.setStatus(func(st *ScheduleStatus) {
+			st.NextAttempt = &next
+			st.LastMessage = message
+		})
+		if err := sleepContext(ctx, s.retry); err != nil {
+			return err
+		}
+	}
+}
+
+// runJob dispara um job e espera terminar. Se já houver um job rodando (ex: disparado
+// manualmente), espera por ele e tenta de novo.
+func (s *scheduler) runJob(ctx context.Context, kind string) (Job, error) {
+	for {
+		job, err := s.jobs.Start(kind)
+		if errors.Is(err, errJobAlreadyRunning) {
+			log.Printf("[agendador] Aguardando job %s (%s) terminar.", job.ID, job.Kind)
+			if _, err := s.jobs.Wait(ctx, job.ID); err != nil && ctx.Err() != nil {
+				return Job{}, err
+			}
+			continue
+		}
+		if err != nil {
+			return Job{}, err
+		}
+		s.setStatus(func(st *ScheduleStatus) { st.LastJob = job.ID })
+		job, err = s.jobs.Wait(ctx, job.ID)
+		if err != nil {
+			return job, err
+		}
+		if job.Status != JobSucceeded {
+			return job, fmt.Errorf("job %s terminou com status %s: %s", job.ID, job.Status, job.Error)
+		}
+		return job, nil
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -16,8 +17,8 @@ const copomListURL = "https://www.bcb.gov.br/publicacoes/atascopom/cronologicos"
 const investingURL = "https://br.investing.com/currencies/usd-brl-historical-data"
 const seleniumPort = 9515
 
-func getIPCAPorScraping(wd selenium.WebDriver) (map[string]float64, error) {
-	log.Println("[Scraping IPCA] Iniciando extração do IPCA do IBGE...")
+func getIPCAPorScraping(wd selenium.WebDriver, logger *log.Logger) (map[string]float64, error) {
+	logger.Println("[Scraping IPCA] Iniciando extração do IPCA do IBGE...")
 	url := "https://www.ibge.gov.br/estatisticas/economicas/precos-e-custos/9256-indice-nacional-de-precos-ao-consumidor-amplo.html?=&t=series-historicas"
 
 	if err := wd.Get(url); err != nil {
@@ -111,12 +112,12 @@ func getIPCAPorScraping(wd selenium.WebDriver) (map[string]float64, error) {
 		ipcaMap[key] = valFloat
 	}
 
-	log.Printf("[Scraping IPCA] Extraídos %d registros de IPCA.", len(ipcaMap))
+	logger.Printf("[Scraping IPCA] Extraídos %d registros de IPCA.", len(ipcaMap))
 	return ipcaMap, nil
 }
 
-func getDolarPorScraping(wd selenium.WebDriver, dataYMD string) (float64, error) {
-	log.Printf("Iniciando scraping do Dólar para a data: %s", dataYMD)
+func getDolarPorScraping(wd selenium.WebDriver, logger *log.Logger, dataYMD string) (float64, error) {
+	logger.Printf("Iniciando scraping do Dólar para a data: %s", dataYMD)
 
 	t, err := time.Parse("2006-01-02", dataYMD)
 	if err != nil {
@@ -130,21 +131,21 @@ func getDolarPorScraping(wd selenium.WebDriver, dataYMD string) (float64, error)
 	dataInicio := diaAnterior.Format("2006-01-02")
 	dataFim := t.Format("2006-01-02")
 
-	log.Printf("[Scraping Dólar] Buscando dados entre %s e %s", dataInicio, dataFim)
+	logger.Printf("[Scraping Dólar] Buscando dados entre %s e %s", dataInicio, dataFim)
 
-	log.Println("[Scraping Dólar] 1. Navegando para a URL do Investing...")
+	logger.Println("[Scraping Dólar] 1. Navegando para a URL do Investing...")
 	if err := wd.Get(investingURL); err != nil {
 		return 0, fmt.Errorf("falha ao abrir a URL do Investing: %v", err)
 	}
-	log.Println("[Scraping Dólar] 1. Navegação concluída.")
+	logger.Println("[Scraping Dólar] 1. Navegação concluída.")
 
 	// Estratégia Otimizada: Usar fetch() via JavaScript diretamente após carregar a página
 	// Isso evita a interação com banners, popups e o DatePicker, usando a sessão do navegador
-	log.Println("[Scraping Dólar] 2. Buscando dados via API interna (fetch)...")
+	logger.Println("[Scraping Dólar] 2. Buscando dados via API interna (fetch)...")
 
 	// Estratégia Híbrida: Usar fetch() via JavaScript para buscar dados da API interna
 	// Isso evita a interação com o DatePicker e usa a sessão do navegador para passar pelo Cloudflare
-	log.Println("[Scraping Dólar] 7. Buscando dados via API interna (fetch)...")
+	logger.Println("[Scraping Dólar] 7. Buscando dados via API interna (fetch)...")
 
 	script := `
 		var done = arguments[arguments.length - 1];
@@ -211,7 +212,7 @@ func getDolarPorScraping(wd selenium.WebDriver, dataYMD string) (float64, error)
 		if f, ok := val.(float64); ok {
 			price = f
 		} else {
-			log.Printf("[Scraping Dólar] last_closeRaw não é float64: %T", val)
+			logger.Printf("[Scraping Dólar] last_closeRaw não é float64: %T", val)
 		}
 	}
 
@@ -240,14 +241,16 @@ func getDolarPorScraping(wd selenium.WebDriver, dataYMD string) (float64, error)
 		return 0, fmt.Errorf("campo de preço não identificado ou inválido no JSON")
 	}
 
-	log.Printf("[Scraping Dólar] Preço extraído: %.4f", price)
+	logger.Printf("[Scraping Dólar] Preço extraído: %.4f", price)
 	return price, nil
 }
 
-func scrapeCopomAtas(existingMeetings map[int]bool, onSave func(CopomAta) error) error {
+// scrapeCopomAtas percorre a lista de atas do BCB e chama onSave para cada ata nova.
+// O cancelamento de ctx é verificado entre uma ata e outra.
+func scrapeCopomAtas(ctx context.Context, logger *log.Logger, progress progressFunc, existingMeetings map[int]bool, onSave func(CopomAta) error) error {
 	service, err := selenium.NewChromeDriverService("./chromedriver-linux64/chromedriver", seleniumPort)
 	if err != nil {
-		log.Printf("Erro ao iniciar o ChromeDriverService. Verifique o caminho do chromedriver.")
+		logger.Printf("Erro ao iniciar o ChromeDriverService. Verifique o caminho do chromedriver.")
 		return err
 	}
 	defer service.Stop()
@@ -274,9 +277,9 @@ func scrapeCopomAtas(existingMeetings map[int]bool, onSave func(CopomAta) error)
 	defer wd.Quit()
 
 	// 1. Obter dados do IPCA (histórico completo)
-	ipcaMap, err := getIPCAPorScraping(wd)
+	ipcaMap, err := getIPCAPorScraping(wd, logger)
 	if err != nil {
-		log.Printf("AVISO: Falha ao obter dados do IPCA: %v. O campo valor_ipca ficará vazio.", err)
+		logger.Printf("AVISO: Falha ao obter dados do IPCA: %v. O campo valor_ipca ficará vazio.", err)
 		ipcaMap = make(map[string]float64)
 	}
 
@@ -285,7 +288,7 @@ func scrapeCopomAtas(existingMeetings map[int]bool, onSave func(CopomAta) error)
 		return err
 	}
 
-	log.Println("Aguardando o conteúdo dinâmico carregar (lista de atas)...")
+	logger.Println("Aguardando o conteúdo dinâmico carregar (lista de atas)...")
 	waitTimeout := 3 * time.Second
 	firstLinkSelector := "//div[contains(@class, 'resultados-relacionados')]//h4/a"
 
@@ -324,27 +327,32 @@ func scrapeCopomAtas(existingMeetings map[int]bool, onSave func(CopomAta) error)
 		return fmt.Errorf("nenhum link de ata foi encontrado")
 	}
 
-	log.Printf("Encontrados %d links. Iniciando processamento...", len(links))
+	logger.Printf("Encontrados %d links. Iniciando processamento...", len(links))
+
+	for i, link := range links {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		progress.report(i, len(links))
 
-	for _, link := range links {
 		// Tentar extrair número da reunião do texto do link para pular se já existir
 		num := extractMeetingNumber(link.Text)
 		if num != 0 && existingMeetings[num] {
-			log.Printf("Ata %d já existe. Pulando...", num)
+			logger.Printf("Ata %d já existe. Pulando...", num)
 			continue
 		}
 
 		// Pular atas em PDF (200 a 231)
 		if num >= 200 && num <= 231 {
-			log.Printf("Ata %d ignorada (PDF). Pulando...", num)
+			logger.Printf("Ata %d ignorada (PDF). Pulando...", num)
 			continue
 		}
 
-		log.Printf("-----------------------------------------------------")
-		log.Printf("Processando Ata URL: %s (Texto: %s)", link.URL, link.Text)
+		logger.Printf("-----------------------------------------------------")
+		logger.Printf("Processando Ata URL: %s (Texto: %s)", link.URL, link.Text)
 
 		if err := wd.Get(link.URL); err != nil {
-			log.Printf("AVISO: Falha ao abrir a URL %s. Pulando...", link.URL)
+			logger.Printf("AVISO: Falha ao abrir a URL %s. Pulando...", link.URL)
 			continue
 		}
 
@@ -361,10 +369,10 @@ func scrapeCopomAtas(existingMeetings map[int]bool, onSave func(CopomAta) error)
 		}, waitTimeout)
 
 		if err != nil {
-			log.Printf("AVISO: Timeout ao carregar conteúdo da ata %s. Salvando HTML completo...", link.URL)
+			logger.Printf("AVISO: Timeout ao carregar conteúdo da ata %s. Salvando HTML completo...", link.URL)
 			html, errHTML := wd.PageSource()
 			if errHTML != nil {
-				log.Printf("ERRO: Falha ao obter HTML da página %s após timeout: %v. Pulando...", link.URL, errHTML)
+				logger.Printf("ERRO: Falha ao obter HTML da página %s após timeout: %v. Pulando...", link.URL, errHTML)
 				continue
 			}
 
@@ -378,16 +386,16 @@ func scrapeCopomAtas(existingMeetings map[int]bool, onSave func(CopomAta) error)
 			}
 
 			if err := onSave(ata); err != nil {
-				log.Printf("ERRO ao salvar ata %d (timeout): %v", num, err)
+				logger.Printf("ERRO ao salvar ata %d (timeout): %v", num, err)
 			} else {
-				log.Printf("Ata %d salva com sucesso (HTML completo após timeout).", num)
+				logger.Printf("Ata %d salva com sucesso (HTML completo após timeout).", num)
 			}
 			continue
 		}
 
 		titleElement, err := wd.FindElement(selenium.ByTagName, "h3")
 		if err != nil {
-			log.Printf("AVISO: Não foi possível encontrar o título (h3) na URL %s. Pulando...", link.URL)
+			logger.Printf("AVISO: Não foi possível encontrar o título (h3) na URL %s. Pulando...", link.URL)
 			continue
 		}
 		titulo, _ := titleElement.Text()
@@ -403,13 +411,13 @@ func scrapeCopomAtas(existingMeetings map[int]bool, onSave func(CopomAta) error)
 			if err == nil {
 				conteudo, _ = contentElement.Text()
 			} else {
-				log.Printf("AVISO: Não foi possível extrair o conteúdo estruturado da ata %s. Salvando HTML completo.", link.URL)
+				logger.Printf("AVISO: Não foi possível extrair o conteúdo estruturado da ata %s. Salvando HTML completo.", link.URL)
 				html, errHTML := wd.PageSource()
 				if errHTML == nil {
 					conteudo = html
 					falhaNoParse = true
 				} else {
-					log.Printf("ERRO: Falha ao obter HTML da página %s: %v", link.URL, errHTML)
+					logger.Printf("ERRO: Falha ao obter HTML da página %s: %v", link.URL, errHTML)
 				}
 			}
 		}
@@ -427,7 +435,7 @@ func scrapeCopomAtas(existingMeetings map[int]bool, onSave func(CopomAta) error)
 		// Mas já gastamos o tempo de scraping. Vamos salvar para garantir ou pular?
 		// Se já existe, melhor pular antes. Mas se chegamos aqui, é porque não detectamos antes ou não existia.
 		if ata.NumeroReuniao != 0 && existingMeetings[ata.NumeroReuniao] {
-			log.Printf("Ata %d detectada após scraping (título), mas já existe na base. Não salvando duplicata.", ata.NumeroReuniao)
+			logger.Printf("Ata %d detectada após scraping (título), mas já existe na base. Não salvando duplicata.", ata.NumeroReuniao)
 			continue
 		}
 
@@ -435,22 +443,22 @@ func scrapeCopomAtas(existingMeetings map[int]bool, onSave func(CopomAta) error)
 
 		dataReuniao, err = extractDateFromURL(link.URL)
 		if err != nil {
-			log.Printf("AVISO: Não foi possível extrair data da URL (%s). Tentando extrair do conteúdo...", link.URL)
+			logger.Printf("AVISO: Não foi possível extrair data da URL (%s). Tentando extrair do conteúdo...", link.URL)
 			dataReuniao, err = extractDateFromContent(conteudo)
 		}
 
 		if err != nil {
-			log.Printf("AVISO: FALHA AO EXTRAIR DATA: Não foi possível extrair nem da URL nem do conteúdo: %s. %v", link.URL, err)
+			logger.Printf("AVISO: FALHA AO EXTRAIR DATA: Não foi possível extrair nem da URL nem do conteúdo: %s. %v", link.URL, err)
 		} else {
 			ata.DataReuniao = dataReuniao
-			log.Printf("Data da reunião extraída: %s", dataReuniao)
+			logger.Printf("Data da reunião extraída: %s", dataReuniao)
 
-			dolar, err := getDolarPorScraping(wd, dataReuniao)
+			dolar, err := getDolarPorScraping(wd, logger, dataReuniao)
 			if err != nil {
-				log.Printf("AVISO: Falha ao fazer scraping do dólar para data %s: %v", dataReuniao, err)
+				logger.Printf("AVISO: Falha ao fazer scraping do dólar para data %s: %v", dataReuniao, err)
 			} else {
 				ata.ValorDolar = dolar
-				log.Printf("Dólar (Scraping) encontrado: %.4f", dolar)
+				logger.Printf("Dólar (Scraping) encontrado: %.4f", dolar)
 			}
 
 			// Buscar IPCA correspondente (YYYY-MM)
@@ -458,27 +466,30 @@ func scrapeCopomAtas(existingMeetings map[int]bool, onSave func(CopomAta) error)
 				mesAno := dataReuniao[:7] // "2023-10"
 				if val, ok := ipcaMap[mesAno]; ok {
 					ata.ValorIPCA = val
-					log.Printf("IPCA encontrado para %s: %.2f%%", mesAno, val)
+					logger.Printf("IPCA encontrado para %s: %.2f%%", mesAno, val)
 				} else {
-					log.Printf("AVISO: IPCA não encontrado para o mês %s", mesAno)
+					logger.Printf("AVISO: IPCA não encontrado para o mês %s", mesAno)
 				}
 			}
 		}
 
 		// Salvar imediatamente
 		if err := onSave(ata); err != nil {
-			log.Printf("ERRO CRÍTICO: Falha ao salvar ata %d: %v", ata.NumeroReuniao, err)
+			logger.Printf("ERRO CRÍTICO: Falha ao salvar ata %d: %v", ata.NumeroReuniao, err)
 		} else {
-			log.Printf("Ata %d salva com sucesso.", ata.NumeroReuniao)
+			logger.Printf("Ata %d salva com sucesso.", ata.NumeroReuniao)
 			// Atualizar mapa em memória para evitar reprocessamento futuro na mesma execução (se houver duplicatas nos links)
 			if ata.NumeroReuniao != 0 {
 				existingMeetings[ata.NumeroReuniao] = true
 			}
 		}
 
-		time.Sleep(200 * time.Millisecond)
+		if err := sleepContext(ctx, 200*time.Millisecond); err != nil {
+			return err
+		}
 	}
+	progress.report(len(links), len(links))
 
-	log.Printf("-----------------------------------------------------")
+	logger.Printf("-----------------------------------------------------")
 	return nil
 }
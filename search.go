@@ -0,0 +1,363 @@
+package main
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Parâmetros do BM25
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Tamanho aproximado (em caracteres) do trecho retornado em cada resultado
+const snippetRadius = 90
+
+const (
+	searchKindAta       = "ata"
+	searchKindParagraph = "paragraph"
+)
+
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// foldAccents normaliza para minúsculas sem acentos ("Inflação" -> "inflacao").
+func foldAccents(s string) string {
+	return accentFolder.Replace(strings.ToLower(s))
+}
+
+var stopwords = map[string]bool{
+	"a": true, "o": true, "e": true, "as": true, "os": true, "um": true, "uma": true, "uns": true, "umas": true,
+	"de": true, "da": true, "do": true, "das": true, "dos": true, "em": true, "na": true, "no": true, "nas": true, "nos": true,
+	"ao": true, "aos": true, "para": true, "por": true, "pela": true, "pelo": true, "pelas": true, "pelos": true,
+	"com": true, "sem": true, "que": true, "se": true, "ou": true, "mais": true, "menos": true, "como": true,
+	"sua": true, "seu": true, "suas": true, "seus": true, "esse": true, "essa": true, "este": true, "esta": true,
+	"isso": true, "isto": true, "ja": true, "nao": true, "ser": true, "foi": true, "sao": true, "tem": true,
+	"entre": true, "sobre": true, "ate": true, "apos": true, "tambem": true, "ainda": true, "bem": true,
+}
+
+// Sufixos removidos pelo stemmer, em ordem de tentativa dentro de cada etapa (versão
+// simplificada do RSLP). Cada regra só se aplica se sobrar um radical com pelo menos minStem letras.
+type stemRule struct {
+	suffix      string
+	replacement string
+	minStem     int
+}
+
+var (
+	stemPluralRules = []stemRule{
+		{"oes", "ao", 2}, {"aes", "ao", 2}, {"ais", "al", 2}, {"eis", "el", 2}, {"ois", "ol", 2},
+		{"ns", "m", 2}, {"res", "r", 3}, {"les", "l", 3}, {"s", "", 3},
+	}
+	stemFeminineRules = []stemRule{
+		{"ona", "ao", 3}, {"ora", "or", 3}, {"eira", "eiro", 3}, {"ica", "ico", 3},
+		{"ada", "ado", 2}, {"ida", "ido", 3}, {"iva", "ivo", 3}, {"osa", "oso", 3},
+	}
+	stemNounRules = []stemRule{
+		{"amente", "", 4}, {"mente", "", 4}, {"izacao", "", 4}, {"acao", "", 3}, {"icao", "", 3},
+		{"idade", "", 4}, {"agem", "", 3}, {"ismo", "", 3}, {"ista", "", 4}, {"avel", "", 3},
+		{"ivel", "", 3}, {"ancia", "", 3}, {"encia", "", 3}, {"mento", "", 3}, {"ador", "", 3},
+		{"ario", "", 3}, {"ivo", "", 3}, {"oso", "", 3},
+	}
+	stemVerbRules = []stemRule{
+		{"ariam", "", 2}, {"eriam", "", 2}, {"aram", "", 2}, {"eram", "", 2}, {"iram", "", 2},
+		{"avam", "", 2}, {"ando", "", 2}, {"endo", "", 3}, {"indo", "", 3}, {"ado", "", 2},
+		{"ido", "", 3}, {"ava", "", 2}, {"ar", "", 2}, {"er", "", 2}, {"ir", "", 3}, {"ou", "", 3},
+		{"am", "", 2}, {"em", "", 3},
+	}
+)
+
+func applyStemRules(word string, rules []stemRule) (string, bool) {
+	for _, r := range rules {
+		if strings.HasSuffix(word, r.suffix) && len(word)-len(r.suffix) >= r.minStem {
+			return word[:len(word)-len(r.suffix)] + r.replacement, true
+		}
+	}
+	return word, false
+}
+
+// stemPortuguese reduz uma palavra já sem acentos ao seu radical aproximado, de modo que
+// "desancoragem", "desancoradas" e "desancorado" caiam no mesmo termo do índice.
+func stemPortuguese(word string) string {
+	if len(word) <= 3 {
+		return word
+	}
+	word, _ = applyStemRules(word, stemPluralRules)
+	word, _ = applyStemRules(word, stemFeminineRules)
+	if w, ok := applyStemRules(word, stemNounRules); ok {
+		word = w
+	} else {
+		word, _ = applyStemRules(word, stemVerbRules)
+	}
+	if len(word) > 3 && strings.ContainsAny(word[len(word)-1:], "aeo") {
+		word = word[:len(word)-1]
+	}
+	return word
+}
+
+// searchToken é uma palavra do texto original com sua posição (em bytes) e termo normalizado.
+type searchToken struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// tokenizeForSearch quebra o texto em palavras normalizadas, descartando stopwords.
+func tokenizeForSearch(text string) []searchToken {
+	var tokens []searchToken
+	add := func(start, end int) {
+		word := foldAccents(text[start:end])
+		if len(word) < 2 || stopwords[word] {
+			return
+		}
+		tokens = append(tokens, searchToken{Term: stemPortuguese(word), Start: start, End: end})
+	}
+
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+		} else if start >= 0 {
+			add(start, i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		add(start, len(text))
+	}
+	return tokens
+}
+
+// searchDocument é uma unidade indexada: o texto completo de uma ata ou um parágrafo enriquecido.
+type searchDocument struct {
+	Kind          string
+	MeetingNumber int
+	MeetingDate   string
+	ParagraphID   int
+	GlobalID      int
+	Text          string
+	Length        int
+}
+
+type searchPosting struct {
+	Doc int
+	TF  int
+}
+
+// SearchHit é um resultado da busca textual.
+type SearchHit struct {
+	Kind          string  `json:"kind"` // "ata" ou "paragraph"
+	Score         float64 `json:"score"`
+	MeetingNumber int     `json:"meeting_number"`
+	MeetingDate   string  `json:"meeting_date,omitempty"`
+	ParagraphID   int     `json:"paragraph_id,omitempty"`
+	GlobalID      int     `json:"global_id,omitempty"`
+	Snippet       string  `json:"snippet"` // Trecho com os termos encontrados entre <mark></mark>
+}
+
+// searchIndex é um índice invertido em memória sobre CopomAta.Conteudo e EnrichedParagraph.Paragraph,
+// reconstruído a cada recarga dos datasets.
+type searchIndex struct {
+	mu        sync.RWMutex
+	docs      []searchDocument
+	postings  map[string][]searchPosting
+	avgLength float64
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{postings: make(map[string][]searchPosting)}
+}
+
+// rebuild indexa os dados fora do lock e troca o índice de uma só vez.
+func (idx *searchIndex) rebuild(atas []CopomAta, paragraphs []EnrichedParagraph) {
+	var docs []searchDocument
+	for _, ata := range atas {
+		if ata.Conteudo == "" {
+			continue
+		}
+		text := ata.Conteudo
+		if ata.FalhaNoParse {
+			text = stripHTML(text)
+		}
+		docs = append(docs, searchDocument{Kind: searchKindAta, MeetingNumber: ata.NumeroReuniao, MeetingDate: ata.DataReuniao, Text: text})
+	}
+	for _, p := range paragraphs {
+		docs = append(docs, searchDocument{
+			Kind:          searchKindParagraph,
+			MeetingNumber: p.MeetingNumber,
+			MeetingDate:   p.MeetingDate,
+			ParagraphID:   p.ParagraphID,
+			GlobalID:      p.GlobalID,
+			Text:          p.Paragraph,
+		})
+	}
+
+	postings := make(map[string][]searchPosting)
+	totalLength := 0
+	for i := range docs {
+		tokens := tokenizeForSearch(docs[i].Text)
+		docs[i].Length = len(tokens)
+		totalLength += len(tokens)
+
+		tf := make(map[string]int)
+		for _, t := range tokens {
+			tf[t.Term]++
+		}
+		for term, n := range tf {
+			postings[term] = append(postings[term], searchPosting{Doc: i, TF: n})
+		}
+	}
+	avgLength := 0.0
+	if len(docs) > 0 {
+		avgLength = float64(totalLength) / float64(len(docs))
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.docs = docs
+	idx.postings = postings
+	idx.avgLength = avgLength
+}
+
+// Search retorna os documentos ordenados por BM25. kind vazio busca em atas e parágrafos.
+func (idx *searchIndex) Search(query, kind string, limit int) (int, []SearchHit) {
+	terms := make(map[string]bool)
+	for _, t := range tokenizeForSearch(query) {
+		terms[t.Term] = true
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	scores := make(map[int]float64)
+	n := float64(len(idx.docs))
+	for term := range terms {
+		postings := idx.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		idf := math.Log(1 + (n-float64(len(postings))+0.5)/(float64(len(postings))+0.5))
+		for _, p := range postings {
+			doc := idx.docs[p.Doc]
+			if kind != "" && doc.Kind != kind {
+				continue
+			}
+			tf := float64(p.TF)
+			norm := 1 - bm25B + bm25B*float64(doc.Length)/idx.avgLength
+			scores[p.Doc] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+	}
+
+	ranked := make([]int, 0, len(scores))
+	for doc := range scores {
+		ranked = append(ranked, doc)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if scores[ranked[i]] != scores[ranked[j]] {
+			return scores[ranked[i]] > scores[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+
+	total := len(ranked)
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	hits := make([]SearchHit, 0, len(ranked))
+	for _, i := range ranked {
+		doc := idx.docs[i]
+		hits = append(hits, SearchHit{
+			Kind:          doc.Kind,
+			Score:         math.Round(scores[i]*1000) / 1000,
+			MeetingNumber: doc.MeetingNumber,
+			MeetingDate:   doc.MeetingDate,
+			ParagraphID:   doc.ParagraphID,
+			GlobalID:      doc.GlobalID,
+			Snippet:       buildSnippet(doc.Text, terms),
+		})
+	}
+	return total, hits
+}
+
+// buildSnippet recorta o texto ao redor da primeira ocorrência de um termo buscado e
+// destaca todas as ocorrências do trecho com <mark>. O restante do texto é escapado para HTML.
+func buildSnippet(text string, terms map[string]bool) string {
+	tokens := tokenizeForSearch(text)
+	first := -1
+	for i, t := range tokens {
+		if terms[t.Term] {
+			first = i
+			break
+		}
+	}
+
+	start, end := 0, len(text)
+	if first >= 0 {
+		start = tokens[first].Start - snippetRadius
+		end = tokens[first].End + snippetRadius
+	} else {
+		end = 2 * snippetRadius
+	}
+	start, end = clampToRunes(text, start, end)
+	// Não cortar palavras ao meio nas bordas do trecho
+	for _, t := range tokens {
+		if t.Start < start && start < t.End {
+			start = t.Start
+		}
+		if t.Start < end && end < t.End {
+			end = t.End
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, t := range tokens {
+		if t.Start < start || t.End > end || !terms[t.Term] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:t.Start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[t.Start:t.End]))
+		b.WriteString("</mark>")
+		pos = t.End
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// clampToRunes limita [start, end) ao texto sem cortar caracteres multibyte ao meio.
+func clampToRunes(text string, start, end int) (int, int) {
+	if start < 0 {
+		start = 0
+	}
+	if end > len(text) {
+		end = len(text)
+	}
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	return start, end
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Transmissão dos eventos do pipeline em GET /events (Server-Sent Events).
+//
+// Os últimos eventos ficam em um buffer circular em memória: um cliente que reconecta com
+// Last-Event-ID recebe primeiro o que perdeu e depois os eventos ao vivo. Clientes que não
+// acompanham o ritmo são desconectados (o canal enche) e devem reconectar da mesma forma.
+
+const (
+	defaultEventsBufferSize = 1000
+	// eventClientQueue é o número de eventos pendentes por cliente antes da desconexão.
+	eventClientQueue = 256
+	// eventsHeartbeat é o intervalo dos comentários enviados em conexões ociosas.
+	eventsHeartbeat = 15 * time.Second
+)
+
+// eventsBufferSize permite trocar o tamanho do buffer pela variável EVENTS_BUFFER.
+func eventsBufferSize() int {
+	if val, err := strconv.Atoi(os.Getenv("EVENTS_BUFFER")); err == nil && val > 0 {
+		return val
+	}
+	return defaultEventsBufferSize
+}
+
+// EventGap é enviado (como evento stream.gap) quando parte dos eventos pedidos por
+// Last-Event-ID já saiu do buffer; o cliente deve consultar a API REST para se atualizar.
+type EventGap struct {
+	LastEventID   int64 `json:"last_event_id"`
+	OldestEventID int64 `json:"oldest_event_id"`
+}
+
+type eventStream struct {
+	mu      sync.Mutex
+	size    int
+	buffer  []Event // mais antigo primeiro, no máximo size eventos
+	clients map[chan Event]bool
+}
+
+func newEventStream(size int) *eventStream {
+	return &eventStream{size: size, clients: make(map[chan Event]bool)}
+}
+
+// Handle é o assinante do eventBus: guarda o evento e o repassa aos clientes conectados.
+func (s *eventStream) Handle(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buffer = append(s.buffer, ev)
+	if excess := len(s.buffer) - s.size; excess > 0 {
+		s.buffer = slices.Delete(s.buffer, 0, excess)
+	}
+	for ch := range s.clients {
+		select {
+		case ch <- ev:
+		default:
+			delete(s.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe registra um cliente e retorna os eventos do buffer posteriores a lastID, sem
+// perder nem repetir eventos publicados durante a chamada. gap indica que eventos
+// posteriores a lastID já foram descartados do buffer (ou que o servidor reiniciou).
+func (s *eventStream) Subscribe(lastID int64) (replay []Event, ch chan Event, gap *EventGap) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if lastID > 0 && len(s.buffer) > 0 {
+		oldest, newest := s.buffer[0].ID, s.buffer[len(s.buffer)-1].ID
+		requested := lastID
+		// ID de antes de um reinício do servidor (a numeração recomeça do 1): envia tudo
+		restarted := lastID > newest
+		if restarted {
+			lastID = 0
+		}
+		for _, ev := range s.buffer {
+			if ev.ID > lastID {
+				replay = append(replay, ev)
+			}
+		}
+		if restarted || oldest > lastID+1 {
+			gap = &EventGap{LastEventID: requested, OldestEventID: oldest}
+		}
+	}
+	ch = make(chan Event, eventClientQueue)
+	s.clients[ch] = true
+	return replay, ch, gap
+}
+
+// Unsubscribe remove o cliente (se ainda não foi desconectado por lentidão).
+func (s *eventStream) Unsubscribe(ch chan Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.clients[ch] {
+		delete(s.clients, ch)
+		close(ch)
+	}
+}
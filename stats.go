@@ -0,0 +1,397 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Estatísticas descritivas e de qualidade dos datasets, servidas em GET /stats e
+// impressas por -mode=stats. Substitui o antigo script analise_dataset.py: como é calculado
+// sobre os mesmos dados servidos pela API, os números nunca divergem.
+
+// NumericStats resume uma série numérica. StdDev é o desvio padrão amostral (n-1),
+// como o statistics.stdev do Python.
+type NumericStats struct {
+	Count  int     `json:"count"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"stddev"`
+	// Outliers pelo critério de 1,5 × IQR
+	Outliers []float64 `json:"outliers"`
+}
+
+// FieldCompleteness indica quantas atas têm o campo preenchido (não vazio/zero).
+type FieldCompleteness struct {
+	Field   string  `json:"field"`
+	Filled  int     `json:"filled"`
+	Empty   int     `json:"empty"`
+	Percent float64 `json:"percent"`
+}
+
+// DateGap é um intervalo de mais de maxMeetingGapDays dias sem reunião.
+type DateGap struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Days int    `json:"days"`
+}
+
+// AtasStats reúne as métricas de dataset_raw.json.
+type AtasStats struct {
+	Total             int                 `json:"total"`
+	PerYear           map[string]int      `json:"per_year"`
+	FirstDate         string              `json:"first_date,omitempty"`
+	LastDate          string              `json:"last_date,omitempty"`
+	Gaps              []DateGap           `json:"gaps"`
+	Dollar            *NumericStats       `json:"dollar"`
+	IPCA              *NumericStats       `json:"ipca"`
+	ContentLength     *NumericStats       `json:"content_length"`
+	DollarIPCACorr    *float64            `json:"dollar_ipca_correlation"`
+	Completeness      []FieldCompleteness `json:"completeness"`
+	ParseFailures     int                 `json:"parse_failures"`
+	ParseFailureRate  float64             `json:"parse_failure_rate"`
+	DuplicateMeetings []int               `json:"duplicate_meetings"`
+	Quality           string              `json:"quality"`
+}
+
+// EnrichedStats reúne as métricas de dataset_enriched.json.
+type EnrichedStats struct {
+	Total                int            `json:"total"`
+	Meetings             int            `json:"meetings"`
+	ParagraphsPerMeeting *NumericStats  `json:"paragraphs_per_meeting"`
+	DollarTrends         map[string]int `json:"dollar_trends"`
+	IPCATrends           map[string]int `json:"ipca_trends"`
+	Topics               map[string]int `json:"topics"` // parágrafos por tópico (um parágrafo conta em cada tópico que tiver)
+	WithoutTopics        int            `json:"without_topics"`
+}
+
+// StatsResponse é o corpo de GET /stats.
+type StatsResponse struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	Atas        AtasStats     `json:"atas"`
+	Enriched    EnrichedStats `json:"enriched"`
+}
+
+const maxMeetingGapDays = 60
+
+// trendInvalid agrupa rótulos fora de SUBIR/DESCER/NEUTRO (inclusive vazios).
+const trendInvalid = "INVALIDO"
+
+// ataCompletenessFields lista os campos verificados na completude, na ordem do relatório.
+var ataCompletenessFields = []struct {
+	name   string
+	filled func(CopomAta) bool
+}{
+	{"numero_reuniao", func(a CopomAta) bool { return a.NumeroReuniao != 0 }},
+	{"url", func(a CopomAta) bool { return a.URL != "" }},
+	{"titulo", func(a CopomAta) bool { return a.Titulo != "" }},
+	{"data_reuniao", func(a CopomAta) bool { return a.DataReuniao != "" }},
+	{"valor_dolar", func(a CopomAta) bool { return a.ValorDolar != 0 }},
+	{"valor_ipca", func(a CopomAta) bool { return a.ValorIPCA != 0 }},
+	{"conteudo", func(a CopomAta) bool { return a.Conteudo != "" }},
+}
+
+func describe(values []float64) *NumericStats {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	n := len(sorted)
+
+	s := &NumericStats{Count: n, Min: sorted[0], Max: sorted[n-1], Outliers: []float64{}}
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	s.Mean = sum / float64(n)
+	if n%2 == 1 {
+		s.Median = sorted[n/2]
+	} else {
+		s.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	if n > 1 {
+		sq := 0.0
+		for _, v := range sorted {
+			sq += (v - s.Mean) * (v - s.Mean)
+		}
+		s.StdDev = math.Sqrt(sq / float64(n-1))
+	}
+
+	q1, q3 := sorted[n/4], sorted[3*n/4]
+	iqr := q3 - q1
+	for _, v := range sorted {
+		if v < q1-1.5*iqr || v > q3+1.5*iqr {
+			s.Outliers = append(s.Outliers, v)
+		}
+	}
+	return s
+}
+
+// pearson retorna a correlação entre xs e ys, ou nil se não houver dados suficientes.
+func pearson(xs, ys []float64) *float64 {
+	n := len(xs)
+	if n < 3 {
+		return nil
+	}
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var num, denX, denY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		num += dx * dy
+		denX += dx * dx
+		denY += dy * dy
+	}
+	if denX == 0 || denY == 0 {
+		return nil
+	}
+	r := num / math.Sqrt(denX*denY)
+	return &r
+}
+
+func computeAtasStats(atas []CopomAta) AtasStats {
+	s := AtasStats{
+		Total:             len(atas),
+		PerYear:           make(map[string]int),
+		Gaps:              []DateGap{},
+		Completeness:      []FieldCompleteness{},
+		DuplicateMeetings: []int{},
+	}
+
+	var dollars, ipcas, lengths, pairX, pairY []float64
+	var dates []time.Time
+	seen := make(map[int]int)
+	filled := make(map[string]int)
+	for _, ata := range atas {
+		if len(ata.DataReuniao) >= 4 {
+			s.PerYear[ata.DataReuniao[:4]]++
+		}
+		if d, err := time.Parse("2006-01-02", ata.DataReuniao); err == nil {
+			dates = append(dates, d)
+		}
+		// Dólar e IPCA zerados indicam falha na consulta (mesmo critério do validate)
+		if ata.ValorDolar > 0 {
+			dollars = append(dollars, ata.ValorDolar)
+		}
+		if ata.ValorIPCA != 0 {
+			ipcas = append(ipcas, ata.ValorIPCA)
+		}
+		if ata.ValorDolar > 0 && ata.ValorIPCA != 0 {
+			pairX = append(pairX, ata.ValorDolar)
+			pairY = append(pairY, ata.ValorIPCA)
+		}
+		if ata.Conteudo != "" {
+			lengths = append(lengths, float64(len([]rune(ata.Conteudo))))
+		}
+		if ata.FalhaNoParse {
+			s.ParseFailures++
+		}
+		if ata.NumeroReuniao != 0 {
+			seen[ata.NumeroReuniao]++
+		}
+
+		for _, f := range ataCompletenessFields {
+			if f.filled(ata) {
+				filled[f.name]++
+			}
+		}
+	}
+
+	for _, f := range ataCompletenessFields {
+		fc := FieldCompleteness{Field: f.name, Filled: filled[f.name], Empty: s.Total - filled[f.name]}
+		if s.Total > 0 {
+			fc.Percent = float64(fc.Filled) / float64(s.Total) * 100
+		}
+		s.Completeness = append(s.Completeness, fc)
+	}
+	for numero, count := range seen {
+		if count > 1 {
+			s.DuplicateMeetings = append(s.DuplicateMeetings, numero)
+		}
+	}
+	slices.Sort(s.DuplicateMeetings)
+
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+	if len(dates) > 0 {
+		s.FirstDate = dates[0].Format("2006-01-02")
+		s.LastDate = dates[len(dates)-1].Format("2006-01-02")
+	}
+	for i := 1; i < len(dates); i++ {
+		if days := int(dates[i].Sub(dates[i-1]).Hours() / 24); days > maxMeetingGapDays {
+			s.Gaps = append(s.Gaps, DateGap{From: dates[i-1].Format("2006-01-02"), To: dates[i].Format("2006-01-02"), Days: days})
+		}
+	}
+
+	s.Dollar = describe(dollars)
+	s.IPCA = describe(ipcas)
+	s.ContentLength = describe(lengths)
+	s.DollarIPCACorr = pearson(pairX, pairY)
+
+	if s.Total > 0 {
+		s.ParseFailureRate = float64(s.ParseFailures) / float64(s.Total)
+		withContent := float64(len(lengths)) / float64(s.Total)
+		switch {
+		case withContent > 0.9:
+			s.Quality = "BOA"
+		case withContent > 0.7:
+			s.Quality = "MODERADA"
+		default:
+			s.Quality = "PRECISA ATENCAO"
+		}
+	}
+	return s
+}
+
+func countTrend(counts map[string]int, trend string) {
+	if !isValidTrend(trend) {
+		trend = trendInvalid
+	}
+	counts[trend]++
+}
+
+func computeEnrichedStats(paragraphs []EnrichedParagraph) EnrichedStats {
+	s := EnrichedStats{
+		Total:        len(paragraphs),
+		DollarTrends: map[string]int{TrendSubir: 0, TrendDescer: 0, TrendNeutro: 0},
+		IPCATrends:   map[string]int{TrendSubir: 0, TrendDescer: 0, TrendNeutro: 0},
+		Topics:       make(map[string]int, len(allTopics)),
+	}
+	for _, topic := range allTopics {
+		s.Topics[topic] = 0
+	}
+	perMeeting := make(map[int]int)
+	for _, p := range paragraphs {
+		perMeeting[p.MeetingNumber]++
+		countTrend(s.DollarTrends, p.Prediction.DollarTrend)
+		countTrend(s.IPCATrends, p.Prediction.IPCATrend)
+		if len(p.Topics) == 0 {
+			s.WithoutTopics++
+		}
+		for _, t := range p.Topics {
+			s.Topics[t]++
+		}
+	}
+	s.Meetings = len(perMeeting)
+	counts := make([]float64, 0, len(perMeeting))
+	for _, n := range perMeeting {
+		counts = append(counts, float64(n))
+	}
+	s.ParagraphsPerMeeting = describe(counts)
+	return s
+}
+
+func computeStats(atas []CopomAta, paragraphs []EnrichedParagraph) StatsResponse {
+	return StatsResponse{
+		GeneratedAt: time.Now(),
+		Atas:        computeAtasStats(atas),
+		Enriched:    computeEnrichedStats(paragraphs),
+	}
+}
+
+// Print imprime o relatório no formato do antigo analise_dataset.py.
+func (s StatsResponse) Print(w io.Writer) {
+	line := strings.Repeat("=", 80)
+	sub := strings.Repeat("-", 40)
+	section := func(title string) {
+		fmt.Fprintf(w, "\n%s\n%s\n%s\n", line, title, line)
+	}
+	numeric := func(title, unit string, st *NumericStats, format string) {
+		fmt.Fprintf(w, "\n%s\n%s\n", title, sub)
+		if st == nil {
+			fmt.Fprintln(w, "  Nenhum valor disponível")
+			return
+		}
+		f := func(v float64) string { return fmt.Sprintf(format, v) + unit }
+		fmt.Fprintf(w, "  Média: %s\n  Mediana: %s\n  Desvio padrão: %s\n", f(st.Mean), f(st.Median), f(st.StdDev))
+		fmt.Fprintf(w, "  Mínimo: %s\n  Máximo: %s\n  Registros com valor: %d\n", f(st.Min), f(st.Max), st.Count)
+		fmt.Fprintf(w, "  Outliers (1,5 × IQR): %d\n", len(st.Outliers))
+	}
+	distribution := func(title string, counts map[string]int, total int) {
+		fmt.Fprintf(w, "\n  %s:\n", title)
+		for _, trend := range []string{TrendSubir, TrendNeutro, TrendDescer, trendInvalid} {
+			n, ok := counts[trend]
+			if !ok {
+				continue
+			}
+			pct := 0.0
+			if total > 0 {
+				pct = float64(n) / float64(total) * 100
+			}
+			fmt.Fprintf(w, "    %-8s %6d (%.1f%%)\n", trend, n, pct)
+		}
+	}
+
+	a := s.Atas
+	fmt.Fprintln(w, "ESTATÍSTICAS DOS DATASETS - ATAS DO COPOM")
+	fmt.Fprintf(w, "Gerado em: %s\n", s.GeneratedAt.Format("2006-01-02 15:04:05"))
+
+	section("1. ATAS (dataset_raw.json)")
+	fmt.Fprintf(w, "\n  Total de atas: %d\n", a.Total)
+	fmt.Fprintln(w, "\n  Reuniões por ano:")
+	years := make([]string, 0, len(a.PerYear))
+	for year := range a.PerYear {
+		years = append(years, year)
+	}
+	slices.Sort(years)
+	for _, year := range years {
+		fmt.Fprintf(w, "    %s: %d atas\n", year, a.PerYear[year])
+	}
+	numeric("ESTATÍSTICAS - VALOR DO DÓLAR (PTAX):", "", a.Dollar, "R$ %.4f")
+	numeric("ESTATÍSTICAS - VALOR DO IPCA:", "%", a.IPCA, "%.4f")
+	numeric("ESTATÍSTICAS - TAMANHO DO CONTEÚDO (CARACTERES):", "", a.ContentLength, "%.0f")
+	if a.DollarIPCACorr != nil {
+		fmt.Fprintf(w, "\n  Correlação Dólar x IPCA: %.4f\n", *a.DollarIPCACorr)
+	}
+
+	section("2. QUALIDADE")
+	fmt.Fprintf(w, "\n  %-20s %-15s %-10s %s\n", "Campo", "Preenchidos", "Vazios", "% Completo")
+	fmt.Fprintln(w, "  "+strings.Repeat("-", 57))
+	for _, fc := range a.Completeness {
+		fmt.Fprintf(w, "  %-20s %-15d %-10d %.1f%%\n", fc.Field, fc.Filled, fc.Empty, fc.Percent)
+	}
+	fmt.Fprintf(w, "\n  Falhas no parse: %d (%.1f%%)\n", a.ParseFailures, a.ParseFailureRate*100)
+	fmt.Fprintf(w, "  Números de reunião duplicados: %d\n", len(a.DuplicateMeetings))
+	if a.FirstDate != "" {
+		fmt.Fprintf(w, "  Primeira ata: %s | Última ata: %s\n", a.FirstDate, a.LastDate)
+	}
+	fmt.Fprintf(w, "  Gaps significativos (>%d dias): %d\n", maxMeetingGapDays, len(a.Gaps))
+	gaps := slices.Clone(a.Gaps)
+	slices.SortFunc(gaps, func(x, y DateGap) int { return cmp.Compare(y.Days, x.Days) })
+	for i, g := range gaps {
+		if i == 5 {
+			break
+		}
+		fmt.Fprintf(w, "    %s -> %s: %d dias\n", g.From, g.To, g.Days)
+	}
+
+	e := s.Enriched
+	section("3. PARÁGRAFOS ENRIQUECIDOS (dataset_enriched.json)")
+	fmt.Fprintf(w, "\n  Total de parágrafos: %d em %d reuniões\n", e.Total, e.Meetings)
+	numeric("PARÁGRAFOS POR REUNIÃO:", "", e.ParagraphsPerMeeting, "%.1f")
+	distribution("Tendência do dólar", e.DollarTrends, e.Total)
+	distribution("Tendência do IPCA", e.IPCATrends, e.Total)
+	fmt.Fprintf(w, "\nTópicos\n%s\n", sub)
+	for _, topic := range allTopics {
+		pct := 0.0
+		if e.Total > 0 {
+			pct = float64(e.Topics[topic]) / float64(e.Total) * 100
+		}
+		fmt.Fprintf(w, "    %-24s %6d (%.1f%%)\n", topic, e.Topics[topic], pct)
+	}
+	fmt.Fprintf(w, "    %-24s %6d\n", "(sem tópico)", e.WithoutTopics)
+
+	fmt.Fprintf(w, "\n%s\n  QUALIDADE GERAL: %s\n%s\n", line, a.Quality, line)
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+	"time"
+)
+
+// Kit mínimo de desenho em SVG usado pelos gráficos de charts.go. Não há dependência
+// externa: cada primitiva escreve o elemento correspondente em um strings.Builder.
+
+type svgPoint struct{ X, Y float64 }
+
+type svgDoc struct {
+	b             strings.Builder
+	width, height float64
+}
+
+func newSVG(width, height float64) *svgDoc {
+	s := &svgDoc{width: width, height: height}
+	fmt.Fprintf(&s.b, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n", width, height, width, height)
+	s.rect(0, 0, width, height, "white", "none", 1)
+	return s
+}
+
+// String fecha o documento e retorna o SVG completo.
+func (s *svgDoc) String() string {
+	return s.b.String() + "</svg>\n"
+}
+
+func (s *svgDoc) line(x1, y1, x2, y2 float64, stroke string, width float64, dash string) {
+	fmt.Fprintf(&s.b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%g"%s/>`+"\n",
+		x1, y1, x2, y2, stroke, width, dashAttr(dash))
+}
+
+func (s *svgDoc) rect(x, y, w, h float64, fill, stroke string, opacity float64) {
+	fmt.Fprintf(&s.b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="%s" fill-opacity="%g"/>`+"\n",
+		x, y, math.Max(w, 0), math.Max(h, 0), fill, stroke, opacity)
+}
+
+func (s *svgDoc) circle(cx, cy, r float64, fill string, opacity float64) {
+	fmt.Fprintf(&s.b, `<circle cx="%.1f" cy="%.1f" r="%g" fill="%s" fill-opacity="%g" stroke="white" stroke-width="0.5"/>`+"\n",
+		cx, cy, r, fill, opacity)
+}
+
+func (s *svgDoc) polyline(points []svgPoint, stroke string, width float64, dash string) {
+	if len(points) == 0 {
+		return
+	}
+	fmt.Fprintf(&s.b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%g" stroke-linejoin="round"%s/>`+"\n",
+		pointsAttr(points), stroke, width, dashAttr(dash))
+}
+
+func (s *svgDoc) polygon(points []svgPoint, fill string, opacity float64) {
+	if len(points) == 0 {
+		return
+	}
+	fmt.Fprintf(&s.b, `<polygon points="%s" fill="%s" fill-opacity="%g" stroke="none"/>`+"\n", pointsAttr(points), fill, opacity)
+}
+
+// text escreve um rótulo; anchor é start, middle ou end e rotate (graus) gira em torno de (x, y).
+func (s *svgDoc) text(x, y float64, txt, anchor string, size float64, fill string, rotate float64) {
+	transform := ""
+	if rotate != 0 {
+		transform = fmt.Sprintf(` transform="rotate(%g %.1f %.1f)"`, rotate, x, y)
+	}
+	fmt.Fprintf(&s.b, `<text x="%.1f" y="%.1f" text-anchor="%s" font-size="%g" fill="%s"%s>%s</text>`+"\n",
+		x, y, anchor, size, fill, transform, html.EscapeString(txt))
+}
+
+func dashAttr(dash string) string {
+	if dash == "" {
+		return ""
+	}
+	return fmt.Sprintf(` stroke-dasharray="%s"`, dash)
+}
+
+func pointsAttr(points []svgPoint) string {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = fmt.Sprintf("%.1f,%.1f", p.X, p.Y)
+	}
+	return strings.Join(parts, " ")
+}
+
+// --- Escalas e eixos ---
+
+type linearScale struct {
+	d0, d1 float64 // domínio (dados)
+	r0, r1 float64 // faixa (pixels)
+}
+
+func (s linearScale) at(v float64) float64 {
+	if s.d1 == s.d0 {
+		return (s.r0 + s.r1) / 2
+	}
+	return s.r0 + (v-s.d0)/(s.d1-s.d0)*(s.r1-s.r0)
+}
+
+// paddedRange devolve [min, max] com folga de 5% (ou ±1 se os valores forem iguais).
+func paddedRange(values ...float64) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	if math.IsInf(lo, 1) {
+		return 0, 1
+	}
+	if lo == hi {
+		return lo - 1, hi + 1
+	}
+	pad := (hi - lo) * 0.05
+	return lo - pad, hi + pad
+}
+
+// niceTicks gera até ~n marcas "redondas" (1, 2, 5 × 10^k) dentro de [lo, hi].
+func niceTicks(lo, hi float64, n int) []float64 {
+	if hi <= lo || n < 1 {
+		return []float64{lo}
+	}
+	raw := (hi - lo) / float64(n)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := mag
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*mag >= raw {
+			step = m * mag
+			break
+		}
+	}
+	var ticks []float64
+	for v := math.Ceil(lo/step) * step; v <= hi+step*1e-9; v += step {
+		ticks = append(ticks, math.Round(v/step)*step)
+	}
+	return ticks
+}
+
+// dayNumber converte datas para o eixo x (dias desde a época).
+func dayNumber(t time.Time) float64 {
+	return float64(t.Unix()) / 86400
+}
+
+type legendItem struct {
+	Label string
+	Color string
+	Dash  string
+	Box   bool // quadrado preenchido em vez de linha
+}
+
+// plotFrame é a área de desenho de um gráfico cartesiano com título e rótulos dos eixos.
+type plotFrame struct {
+	svg                      *svgDoc
+	left, top, right, bottom float64
+	x, y                     linearScale
+}
+
+const (
+	chartWidth  = 1000.0
+	chartHeight = 500.0
+)
+
+// newPlotFrame cria o documento com título e rótulos. rightMargin maior abre espaço para um
+// segundo eixo y.
+func newPlotFrame(width, height, rightMargin float64, title, xLabel, yLabel string) *plotFrame {
+	p := &plotFrame{svg: newSVG(width, height), left: 75, top: 50, right: width - rightMargin, bottom: height - 65}
+	p.svg.text(width/2, 28, title, "middle", 15, "#222", 0)
+	if xLabel != "" {
+		p.svg.text((p.left+p.right)/2, height-12, xLabel, "middle", 12, "#333", 0)
+	}
+	if yLabel != "" {
+		p.svg.text(18, (p.top+p.bottom)/2, yLabel, "middle", 12, "#333", -90)
+	}
+	return p
+}
+
+func (p *plotFrame) setX(lo, hi float64) { p.x = linearScale{lo, hi, p.left, p.right} }
+func (p *plotFrame) setY(lo, hi float64) { p.y = linearScale{lo, hi, p.bottom, p.top} }
+
+// yAxis desenha grade horizontal e rótulos do eixo y à esquerda.
+func (p *plotFrame) yAxis(format func(float64) string) {
+	for _, v := range niceTicks(p.y.d0, p.y.d1, 6) {
+		y := p.y.at(v)
+		p.svg.line(p.left, y, p.right, y, "#e5e5e5", 1, "")
+		p.svg.text(p.left-8, y+4, format(v), "end", 11, "#444", 0)
+	}
+	p.svg.line(p.left, p.bottom, p.right, p.bottom, "#888", 1, "")
+	p.svg.line(p.left, p.top, p.left, p.bottom, "#888", 1, "")
+}
+
+// secondaryYAxis desenha os rótulos de outra escala no lado direito, na cor da série.
+func (p *plotFrame) secondaryYAxis(scale linearScale, label, color string, format func(float64) string) {
+	for _, v := range niceTicks(scale.d0, scale.d1, 6) {
+		p.svg.text(p.right+8, scale.at(v)+4, format(v), "start", 11, color, 0)
+	}
+	p.svg.line(p.right, p.top, p.right, p.bottom, "#888", 1, "")
+	p.svg.text(p.svg.width-15, (p.top+p.bottom)/2, label, "middle", 12, color, 90)
+}
+
+// xYearAxis marca o início de cada ano (ou a cada 2+ anos em períodos longos).
+func (p *plotFrame) xYearAxis(from, to time.Time) {
+	step := max(1, (to.Year()-from.Year()+1)/12)
+	for year := from.Year(); year <= to.Year()+1; year += step {
+		t := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		x := p.x.at(dayNumber(t))
+		if x < p.left || x > p.right {
+			continue
+		}
+		p.svg.line(x, p.top, x, p.bottom, "#f0f0f0", 1, "")
+		p.svg.line(x, p.bottom, x, p.bottom+5, "#888", 1, "")
+		p.svg.text(x, p.bottom+18, fmt.Sprint(year), "middle", 11, "#444", 0)
+	}
+}
+
+// xValueAxis marca valores numéricos no eixo x (gráficos de dispersão).
+func (p *plotFrame) xValueAxis(format func(float64) string) {
+	for _, v := range niceTicks(p.x.d0, p.x.d1, 8) {
+		x := p.x.at(v)
+		p.svg.line(x, p.top, x, p.bottom, "#f0f0f0", 1, "")
+		p.svg.text(x, p.bottom+18, format(v), "middle", 11, "#444", 0)
+	}
+}
+
+// hline desenha uma linha horizontal de referência no valor v do eixo y.
+func (p *plotFrame) hline(v float64, color, dash string) {
+	if v < math.Min(p.y.d0, p.y.d1) || v > math.Max(p.y.d0, p.y.d1) {
+		return
+	}
+	y := p.y.at(v)
+	p.svg.line(p.left, y, p.right, y, color, 1, dash)
+}
+
+func (p *plotFrame) legend(items []legendItem, anchorRight bool) {
+	if len(items) == 0 {
+		return
+	}
+	width := 0.0
+	for _, it := range items {
+		width = math.Max(width, float64(len([]rune(it.Label)))*6.5+40)
+	}
+	x := p.left + 10
+	if anchorRight {
+		x = p.right - width - 10
+	}
+	y := p.top + 10
+	p.svg.rect(x, y, width, float64(len(items))*18+8, "white", "#ccc", 0.85)
+	for i, it := range items {
+		ly := y + 15 + float64(i)*18
+		if it.Box {
+			p.svg.rect(x+8, ly-9, 16, 10, it.Color, "none", 0.8)
+		} else {
+			p.svg.line(x+8, ly-4, x+24, ly-4, it.Color, 2, it.Dash)
+		}
+		p.svg.text(x+30, ly, it.Label, "start", 11, "#333", 0)
+	}
+}
+
+// emptyChart é exibido quando o filtro de datas não deixa dados para o gráfico.
+func emptyChart(title string) string {
+	svg := newSVG(chartWidth, chartHeight)
+	svg.text(chartWidth/2, 28, title, "middle", 15, "#222", 0)
+	svg.text(chartWidth/2, chartHeight/2, "Sem dados no período selecionado", "middle", 14, "#888", 0)
+	return svg.String()
+}
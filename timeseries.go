@@ -0,0 +1,145 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Séries disponíveis em GET /timeseries.
+const (
+	seriesDollar          = "dollar"
+	seriesIPCA            = "ipca"
+	seriesDollarSentiment = "dollar_sentiment"
+	seriesIPCASentiment   = "ipca_sentiment"
+)
+
+var allSeries = []string{seriesDollar, seriesIPCA, seriesDollarSentiment, seriesIPCASentiment}
+
+// TimeSeriesResponse traz as séries alinhadas por reunião: o i-ésimo valor de cada série
+// corresponde a Dates[i]/Meetings[i]. Valores ausentes (dólar/IPCA zerados, reunião sem
+// parágrafos enriquecidos) vêm como null.
+type TimeSeriesResponse struct {
+	Dates    []string              `json:"dates"`
+	Meetings []int                 `json:"meetings"`
+	Series   map[string][]*float64 `json:"series"`
+}
+
+// parseSeries interpreta "dollar,ipca_sentiment"; vazio significa todas as séries.
+func parseSeries(spec string) ([]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		return allSeries, nil
+	}
+	var series []string
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || slices.Contains(series, name) {
+			continue
+		}
+		if !slices.Contains(allSeries, name) {
+			return nil, fmt.Errorf("Série '%s' desconhecida (use %s).", name, strings.Join(allSeries, ", "))
+		}
+		series = append(series, name)
+	}
+	return series, nil
+}
+
+// trendSentiment resume os rótulos de uma reunião em (SUBIR − DESCER) / n, de -1 a 1.
+func trendSentiment(paragraphs []EnrichedParagraph, trend func(GeminiPrediction) string) *float64 {
+	if len(paragraphs) == 0 {
+		return nil
+	}
+	score := 0
+	for _, p := range paragraphs {
+		switch trend(p.Prediction) {
+		case TrendSubir:
+			score++
+		case TrendDescer:
+			score--
+		}
+	}
+	s := float64(score) / float64(len(paragraphs))
+	return &s
+}
+
+func nonZero(v float64) *float64 {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
+// buildTimeSeries monta uma linha por reunião com data, ordenada cronologicamente.
+// Dólar e IPCA vêm da ata; reuniões presentes apenas no dataset enriquecido usam os
+// valores copiados para os parágrafos. from/to (YYYY-MM-DD) são inclusivos.
+func buildTimeSeries(atas []CopomAta, paragraphs []EnrichedParagraph, series []string, from, to string) TimeSeriesResponse {
+	type point struct {
+		meeting      int
+		date         string
+		dollar, ipca float64
+		paragraphs   []EnrichedParagraph
+	}
+
+	points := make(map[int]*point)
+	for _, ata := range atas {
+		if ata.NumeroReuniao == 0 || ata.DataReuniao == "" {
+			continue
+		}
+		points[ata.NumeroReuniao] = &point{
+			meeting: ata.NumeroReuniao,
+			date:    ata.DataReuniao,
+			dollar:  ata.ValorDolar,
+			ipca:    ata.ValorIPCA,
+		}
+	}
+	for _, p := range paragraphs {
+		pt, ok := points[p.MeetingNumber]
+		if !ok {
+			if p.MeetingDate == "" {
+				continue
+			}
+			pt = &point{meeting: p.MeetingNumber, date: p.MeetingDate, dollar: p.DollarValue, ipca: p.IPCAValue}
+			points[p.MeetingNumber] = pt
+		}
+		pt.paragraphs = append(pt.paragraphs, p)
+	}
+
+	ordered := make([]*point, 0, len(points))
+	for _, pt := range points {
+		if (from == "" || pt.date >= from) && (to == "" || pt.date <= to) {
+			ordered = append(ordered, pt)
+		}
+	}
+	slices.SortFunc(ordered, func(a, b *point) int {
+		return cmp.Or(cmp.Compare(a.date, b.date), cmp.Compare(a.meeting, b.meeting))
+	})
+
+	resp := TimeSeriesResponse{
+		Dates:    make([]string, 0, len(ordered)),
+		Meetings: make([]int, 0, len(ordered)),
+		Series:   make(map[string][]*float64, len(series)),
+	}
+	for _, name := range series {
+		resp.Series[name] = make([]*float64, 0, len(ordered))
+	}
+	for _, pt := range ordered {
+		resp.Dates = append(resp.Dates, pt.date)
+		resp.Meetings = append(resp.Meetings, pt.meeting)
+		for _, name := range series {
+			var v *float64
+			switch name {
+			case seriesDollar:
+				v = nonZero(pt.dollar)
+			case seriesIPCA:
+				v = nonZero(pt.ipca)
+			case seriesDollarSentiment:
+				v = trendSentiment(pt.paragraphs, func(p GeminiPrediction) string { return p.DollarTrend })
+			case seriesIPCASentiment:
+				v = trendSentiment(pt.paragraphs, func(p GeminiPrediction) string { return p.IPCATrend })
+			}
+			resp.Series[name] = append(resp.Series[name], v)
+		}
+	}
+	return resp
+}
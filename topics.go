@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Tópicos atribuídos a cada parágrafo enriquecido (EnrichedParagraph.Topics). Um parágrafo
+// pode ter mais de um tópico; a ordem canônica é a de allTopics.
+const (
+	TopicExternalScenario      = "external_scenario"
+	TopicDomesticActivity      = "domestic_activity"
+	TopicLaborMarket           = "labor_market"
+	TopicInflationExpectations = "inflation_expectations"
+	TopicFiscalPolicy          = "fiscal_policy"
+	TopicExchangeRate          = "exchange_rate"
+	TopicBalanceOfRisks        = "balance_of_risks"
+	TopicForwardGuidance       = "forward_guidance"
+)
+
+var allTopics = []string{
+	TopicExternalScenario,
+	TopicDomesticActivity,
+	TopicLaborMarket,
+	TopicInflationExpectations,
+	TopicFiscalPolicy,
+	TopicExchangeRate,
+	TopicBalanceOfRisks,
+	TopicForwardGuidance,
+}
+
+func isValidTopic(topic string) bool {
+	return slices.Contains(allTopics, topic)
+}
+
+// topicKeywords são as expressões (minúsculas e sem acento, como em foldAccents) usadas pelo
+// classificador offline. Servem de fallback quando o Gemini não retorna tópicos válidos e
+// para classificar parágrafos enriquecidos antes deste campo existir (migração 3).
+var topicKeywords = map[string][]string{
+	TopicExternalScenario: {
+		"cenario externo", "ambiente externo", "economia global", "economias avancadas",
+		"economias emergentes", "estados unidos", "norte-americana", "china", "europa",
+		"cenario internacional", "conjuntura internacional", "federal reserve", "commodities",
+	},
+	TopicDomesticActivity: {
+		"atividade economica", "atividade domestica", "crescimento economico", "pib",
+		"demanda agregada", "hiato do produto", "consumo das familias", "investimento",
+		"producao industrial", "setor de servicos", "concessoes de credito",
+	},
+	TopicLaborMarket: {
+		"mercado de trabalho", "desemprego", "desocupacao", "emprego", "rendimento real",
+		"salarios", "massa salarial",
+	},
+	TopicInflationExpectations: {
+		"expectativas de inflacao", "expectativas para a inflacao", "expectativas inflacionarias",
+		"pesquisa focus", "ancoragem", "desancoragem", "ancoradas", "desancoradas",
+	},
+	TopicFiscalPolicy: {
+		"fiscal", "fiscais", "divida publica", "arcabouco", "gastos publicos", "contas publicas",
+		"parafiscal",
+	},
+	TopicExchangeRate: {
+		"cambio", "cambial", "cambiais", "moeda", "dolar", "apreciacao do real", "depreciacao do real",
+	},
+	TopicBalanceOfRisks: {
+		"balanco de riscos", "riscos de alta", "riscos de baixa", "riscos para o cenario",
+		"fatores de risco", "assimetria",
+	},
+	TopicForwardGuidance: {
+		"proximos passos", "proximas reunioes", "proxima reuniao", "interrupcao do ciclo",
+		"ajustes futuros", "periodo bastante prolongado", "nao hesitara", "antecipa",
+		"sinaliza", "magnitude total do ciclo", "ritmo de ajuste", "ritmo de flexibilizacao",
+	},
+}
+
+// classifyTopicsByKeywords atribui tópicos pelas expressões de topicKeywords.
+func classifyTopicsByKeywords(text string) []string {
+	folded := foldAccents(text)
+	var topics []string
+	for _, topic := range allTopics {
+		for _, kw := range topicKeywords[topic] {
+			if strings.Contains(folded, kw) {
+				topics = append(topics, topic)
+				break
+			}
+		}
+	}
+	return topics
+}
+
+// normalizeTopics descarta rótulos desconhecidos e duplicados e devolve os tópicos na ordem
+// canônica. Aceita variações de caixa e hífens no lugar de underscores.
+func normalizeTopics(topics []string) []string {
+	seen := make(map[string]bool, len(topics))
+	for _, t := range topics {
+		t = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(t)), "-", "_")
+		if isValidTopic(t) {
+			seen[t] = true
+		}
+	}
+	var normalized []string
+	for _, topic := range allTopics {
+		if seen[topic] {
+			normalized = append(normalized, topic)
+		}
+	}
+	return normalized
+}
+
+// paragraphTopics usa os tópicos do Gemini quando válidos e o classificador por palavras-chave
+// caso contrário.
+func paragraphTopics(paragraph string, fromModel []string) []string {
+	if topics := normalizeTopics(fromModel); len(topics) > 0 {
+		return topics
+	}
+	return classifyTopicsByKeywords(paragraph)
+}
+
+// hasAnyTopic indica se o parágrafo tem pelo menos um dos tópicos pedidos.
+func hasAnyTopic(p EnrichedParagraph, topics map[string]bool) bool {
+	for _, t := range p.Topics {
+		if topics[t] {
+			return true
+		}
+	}
+	return false
+}
+
+// topicDescriptions explica cada tópico no prompt do Gemini.
+var topicDescriptions = map[string]string{
+	TopicExternalScenario:      "cenário externo e economia global",
+	TopicDomesticActivity:      "atividade econômica doméstica",
+	TopicLaborMarket:           "mercado de trabalho",
+	TopicInflationExpectations: "expectativas de inflação",
+	TopicFiscalPolicy:          "política fiscal",
+	TopicExchangeRate:          "câmbio",
+	TopicBalanceOfRisks:        "balanço de riscos",
+	TopicForwardGuidance:       "comunicação sobre os próximos passos da política monetária (forward guidance)",
+}
+
+// topicPromptList monta a lista de tópicos ("- id: descrição") usada no prompt.
+func topicPromptList() string {
+	var sb strings.Builder
+	for _, topic := range allTopics {
+		fmt.Fprintf(&sb, "- %s: %s\n", topic, topicDescriptions[topic])
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -13,6 +13,17 @@ type CopomAta struct {
 	FalhaNoParse  bool    `json:"falha_no_parse,omitempty"`
 }
 
+// Rótulos de tendência aceitos em GeminiPrediction
+const (
+	TrendSubir  = "SUBIR"
+	TrendDescer = "DESCER"
+	TrendNeutro = "NEUTRO"
+)
+
+func isValidTrend(trend string) bool {
+	return trend == TrendSubir || trend == TrendDescer || trend == TrendNeutro
+}
+
 type GeminiPrediction struct {
 	DollarTrend string `json:"dollar_trend"` // "SUBIR", "DESCER", "NEUTRO"
 	IPCATrend   string `json:"ipca_trend"`   // "SUBIR", "DESCER", "NEUTRO"
@@ -28,7 +39,11 @@ type EnrichedParagraph struct {
 	DollarValue   float64          `json:"dollar_value"`
 	IPCAValue     float64          `json:"ipca_value"`
 	Paragraph     string           `json:"paragraph"`
+	Topics        []string         `json:"topics,omitempty"` // Ver allTopics
 	Prediction    GeminiPrediction `json:"prediction"`
+	// Embedding só é preenchido nas respostas que o pedem (include_embedding); o vetor fica
+	// gravado em dataset_embeddings.json, não no dataset enriquecido.
+	Embedding []float32 `json:"embedding,omitempty"`
 }
 
 type ataStore struct {
@@ -44,6 +59,22 @@ func newAtaStore() *ataStore {
 	}
 }
 
+// replace reconstrói os índices fora do lock e troca o conteúdo do store de uma só vez,
+// para que as requisições em andamento nunca vejam um estado parcial.
+func (s *ataStore) replace(atas []CopomAta) {
+	porNumero := make(map[int]CopomAta, len(atas))
+	for _, ata := range atas {
+		if ata.NumeroReuniao != 0 {
+			porNumero[ata.NumeroReuniao] = ata
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.atas = atas
+	s.atasPorNumero = porNumero
+}
+
 type enrichedStore struct {
 	mu              sync.RWMutex
 	paragraphs      []EnrichedParagraph
@@ -58,3 +89,34 @@ func newEnrichedStore() *enrichedStore {
 		byMeetingNumber: make(map[int][]EnrichedParagraph),
 	}
 }
+
+// all retorna o slice atual. replace sempre troca o slice inteiro (nunca altera o antigo),
+// então o resultado pode ser lido sem segurar o lock.
+func (s *ataStore) all() []CopomAta {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.atas
+}
+
+// replace é o equivalente de ataStore.replace para os parágrafos enriquecidos.
+func (s *enrichedStore) replace(data []EnrichedParagraph) {
+	byGlobalID := make(map[int]EnrichedParagraph, len(data))
+	byMeetingNumber := make(map[int][]EnrichedParagraph)
+	for _, p := range data {
+		byGlobalID[p.GlobalID] = p
+		byMeetingNumber[p.MeetingNumber] = append(byMeetingNumber[p.MeetingNumber], p)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paragraphs = data
+	s.byGlobalID = byGlobalID
+	s.byMeetingNumber = byMeetingNumber
+}
+
+// all é o equivalente de ataStore.all para os parágrafos enriquecidos.
+func (s *enrichedStore) all() []EnrichedParagraph {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.paragraphs
+}
@@ -1,10 +1,8 @@
 package main
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -80,58 +78,38 @@ func extractDateFromURL(url string) (string, error) {
 	return fmt.Sprintf("%s-%s-%s", ano, mes, dia), nil
 }
 
-// --- Persistência ---
+var reHTMLTag = regexp.MustCompile(`<[^>]*>`)
 
-func LoadAtas(filename string) ([]CopomAta, error) {
-	// Se arquivo não existe, retorna vazio
-	// (Poderia checar os.IsNotExist, mas vamos simplificar)
-	file, err := os.Open(filename)
-	if err != nil {
-		return []CopomAta{}, nil
-	}
-	defer file.Close()
-
-	var atas []CopomAta
-	if err := json.NewDecoder(file).Decode(&atas); err != nil {
-		return nil, err
-	}
-	return atas, nil
-}
-
-func SaveAtas(filename string, atas []CopomAta) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(atas)
+// stripHTML remove tags HTML simples para extrair o texto de atas salvas com FalhaNoParse.
+func stripHTML(content string) string {
+	text := reHTMLTag.ReplaceAllString(content, "\n")
+	text = strings.ReplaceAll(text, "&nbsp;", " ")
+	text = strings.ReplaceAll(text, "&amp;", "&")
+	return text
 }
 
-func LoadEnrichedData(filename string) ([]EnrichedParagraph, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return []EnrichedParagraph{}, nil
-	}
-	defer file.Close()
-
-	var data []EnrichedParagraph
-	if err := json.NewDecoder(file).Decode(&data); err != nil {
-		return nil, err
+// splitParagraphs quebra o texto em linhas e as agrega em parágrafos de pelo menos 200
+// caracteres. Os índices (base 1) são os ParagraphID do enriquecimento.
+func splitParagraphs(text string) []string {
+	var paragraphs []string
+	var currentBuffer strings.Builder
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if currentBuffer.Len() > 0 {
+			currentBuffer.WriteString(" ")
+		}
+		currentBuffer.WriteString(line)
+		if currentBuffer.Len() >= 200 {
+			paragraphs = append(paragraphs, currentBuffer.String())
+			currentBuffer.Reset()
+		}
 	}
-	return data, nil
-}
-
-func SaveEnrichedData(filename string, data []EnrichedParagraph) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
+	if currentBuffer.Len() > 0 {
+		paragraphs = append(paragraphs, currentBuffer.String())
 	}
-	defer file.Close()
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(data)
+	return paragraphs
 }
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	severityError   = "ERRO"
+	severityWarning = "AVISO"
+)
+
+// Máximo de exemplos listados por verificação no relatório
+const maxValidationExamples = 10
+
+type validationIssue struct {
+	Severity string
+	Dataset  string
+	Check    string
+	Detail   string
+}
+
+// validationReport acumula os problemas encontrados em dataset_raw.json e dataset_enriched.json.
+type validationReport struct {
+	RawCount      int
+	EnrichedCount int
+	Issues        []validationIssue
+}
+
+func (r *validationReport) add(severity, dataset, check, detail string) {
+	r.Issues = append(r.Issues, validationIssue{Severity: severity, Dataset: dataset, Check: check, Detail: detail})
+}
+
+func (r *validationReport) count(severity string) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *validationReport) Errors() int {
+	return r.count(severityError)
+}
+
+func (r *validationReport) Warnings() int {
+	return r.count(severityWarning)
+}
+
+// validateAtas verifica o dataset bruto: duplicatas, campos obrigatórios e ordem cronológica.
+func validateAtas(r *validationReport, atas []CopomAta) {
+	const dataset = "dataset_raw.json"
+	r.RawCount = len(atas)
+
+	seen := make(map[int]int)
+	for _, ata := range atas {
+		label := fmt.Sprintf("Ata %d (%s)", ata.NumeroReuniao, ata.URL)
+		if ata.NumeroReuniao == 0 {
+			r.add(severityError, dataset, "numero_reuniao ausente (0)", ata.URL)
+		} else {
+			seen[ata.NumeroReuniao]++
+			if seen[ata.NumeroReuniao] == 2 {
+				r.add(severityError, dataset, "numero_reuniao duplicado", fmt.Sprintf("Ata %d", ata.NumeroReuniao))
+			}
+		}
+
+		if ata.DataReuniao == "" {
+			r.add(severityWarning, dataset, "data_reuniao ausente", label)
+		} else if _, err := time.Parse("2006-01-02", ata.DataReuniao); err != nil {
+			r.add(severityError, dataset, "data_reuniao fora do formato YYYY-MM-DD", fmt.Sprintf("%s: %q", label, ata.DataReuniao))
+		}
+		if ata.ValorDolar == 0 {
+			r.add(severityWarning, dataset, "valor_dolar zerado", label)
+		}
+		if ata.ValorIPCA == 0 {
+			r.add(severityWarning, dataset, "valor_ipca zerado", label)
+		}
+	}
+
+	// Datas devem crescer junto com o número da reunião
+	var dated []CopomAta
+	for _, ata := range atas {
+		if ata.NumeroReuniao == 0 || seen[ata.NumeroReuniao] > 1 {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", ata.DataReuniao); err == nil {
+			dated = append(dated, ata)
+		}
+	}
+	sort.Slice(dated, func(i, j int) bool { return dated[i].NumeroReuniao < dated[j].NumeroReuniao })
+	for i := 1; i < len(dated); i++ {
+		prev, cur := dated[i-1], dated[i]
+		if cur.DataReuniao < prev.DataReuniao {
+			r.add(severityError, dataset, "data fora de ordem cronológica",
+				fmt.Sprintf("Ata %d (%s) é anterior à Ata %d (%s)", cur.NumeroReuniao, cur.DataReuniao, prev.NumeroReuniao, prev.DataReuniao))
+		}
+	}
+}
+
+// validateEnriched verifica o dataset enriquecido. Se atas for nil (dataset bruto indisponível),
+// a verificação de parágrafos órfãos é ignorada.
+func validateEnriched(r *validationReport, enriched []EnrichedParagraph, atas []CopomAta) {
+	const dataset = "dataset_enriched.json"
+	r.EnrichedCount = len(enriched)
+
+	var meetings map[int]bool
+	if atas != nil {
+		meetings = make(map[int]bool, len(atas))
+		for _, ata := range atas {
+			meetings[ata.NumeroReuniao] = true
+		}
+	}
+
+	globalIDs := make(map[int]int)
+	paragraphKeys := make(map[[2]int]int)
+	orphans := make(map[int]int)
+	for _, p := range enriched {
+		label := fmt.Sprintf("global_id %d (reunião %d, parágrafo %d)", p.GlobalID, p.MeetingNumber, p.ParagraphID)
+
+		if p.GlobalID == 0 {
+			r.add(severityError, dataset, "global_id ausente (0)", label)
+		} else {
+			globalIDs[p.GlobalID]++
+			if globalIDs[p.GlobalID] == 2 {
+				r.add(severityError, dataset, "global_id duplicado", fmt.Sprintf("global_id %d", p.GlobalID))
+			}
+		}
+
+		key := [2]int{p.MeetingNumber, p.ParagraphID}
+		paragraphKeys[key]++
+		if paragraphKeys[key] == 2 {
+			r.add(severityError, dataset, "parágrafo duplicado (reunião + paragraph_id)", fmt.Sprintf("reunião %d, parágrafo %d", p.MeetingNumber, p.ParagraphID))
+		}
+
+		if meetings != nil && !meetings[p.MeetingNumber] {
+			orphans[p.MeetingNumber]++
+		}
+
+		if !isValidTrend(p.Prediction.DollarTrend) {
+			r.add(severityError, dataset, "dollar_trend inválido", fmt.Sprintf("%s: %q", label, p.Prediction.DollarTrend))
+		}
+		if !isValidTrend(p.Prediction.IPCATrend) {
+			r.add(severityError, dataset, "ipca_trend inválido", fmt.Sprintf("%s: %q", label, p.Prediction.IPCATrend))
+		}
+		for _, t := range p.Topics {
+			if !isValidTopic(t) {
+				r.add(severityError, dataset, "tópico inválido", fmt.Sprintf("%s: %q", label, t))
+			}
+		}
+	}
+
+	orphanMeetings := make([]int, 0, len(orphans))
+	for num := range orphans {
+		orphanMeetings = append(orphanMeetings, num)
+	}
+	sort.Ints(orphanMeetings)
+	for _, num := range orphanMeetings {
+		r.add(severityError, dataset, "parágrafos órfãos (reunião ausente do dataset bruto)", fmt.Sprintf("reunião %d: %d parágrafos", num, orphans[num]))
+	}
+}
+
+// Print escreve o relatório agrupado por verificação, com alguns exemplos de cada.
+func (r *validationReport) Print(w io.Writer) {
+	line := strings.Repeat("=", 80)
+	fmt.Fprintln(w, line)
+	fmt.Fprintln(w, "RELATÓRIO DE VALIDAÇÃO DOS DATASETS")
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "  Atas (dataset_raw.json): %d\n", r.RawCount)
+	fmt.Fprintf(w, "  Parágrafos (dataset_enriched.json): %d\n", r.EnrichedCount)
+
+	type group struct {
+		severity, dataset, check string
+		details                  []string
+	}
+	var groups []*group
+	index := make(map[string]*group)
+	for _, issue := range r.Issues {
+		key := issue.Severity + "|" + issue.Dataset + "|" + issue.Check
+		g, ok := index[key]
+		if !ok {
+			g = &group{severity: issue.Severity, dataset: issue.Dataset, check: issue.Check}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		if issue.Detail != "" {
+			g.details = append(g.details, issue.Detail)
+		}
+	}
+	// Erros primeiro, depois avisos, mantendo a ordem em que foram encontrados
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].severity == severityError && groups[j].severity != severityError
+	})
+
+	for _, g := range groups {
+		fmt.Fprintf(w, "\n[%s] %s: %s (%d)\n", g.severity, g.dataset, g.check, len(g.details))
+		for i, detail := range g.details {
+			if i == maxValidationExamples {
+				fmt.Fprintf(w, "    ... e mais %d\n", len(g.details)-maxValidationExamples)
+				break
+			}
+			fmt.Fprintf(w, "    - %s\n", detail)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, line)
+	fmt.Fprintf(w, "  Erros: %d | Avisos: %d\n", r.Errors(), r.Warnings())
+	fmt.Fprintln(w, line)
+}
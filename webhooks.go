@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Webhooks de saída: os eventos de reunião do eventBus (webhookEventTypes) são enviados por
+// POST (JSON) aos webhooks configurados em webhooks.json (modelo em webhooks.example.json)
+// que assinam aquele tipo de evento.
+//
+// O corpo é assinado com HMAC-SHA256 usando o secret do webhook. O receptor deve recalcular
+// HMAC(secret, "<X-Copom-Timestamp>.<corpo>") e comparar com X-Copom-Signature
+// ("sha256=<hex>"); o timestamp no cálculo evita a reutilização de entregas antigas.
+// Respostas fora de 2xx (ou erro de rede) são repetidas com backoff exponencial.
+
+const (
+	defaultWebhooksFilename   = "webhooks.json"
+	defaultWebhookMaxAttempts = 5
+	webhookInitialBackoff     = 2 * time.Second
+	webhookTimeout            = 10 * time.Second
+	// maxWebhookDeliveries limita as entregas guardadas em memória (GET /admin/webhooks/deliveries).
+	maxWebhookDeliveries = 200
+)
+
+// webhookEventTypes são os eventos enviados aos webhooks; os demais (parágrafos, jobs,
+// recargas) são frequentes demais e ficam apenas em GET /events.
+var webhookEventTypes = []string{EventAtaScraped, EventEnrichmentCompleted}
+
+// Webhook é um destino configurado em webhooks.json.
+type Webhook struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Secret string `json:"secret,omitempty"`
+	// Events restringe os tipos de evento enviados (vazio = todos).
+	Events []string `json:"events,omitempty"`
+}
+
+func (w Webhook) wants(eventType string) bool {
+	if !slices.Contains(webhookEventTypes, eventType) {
+		return false
+	}
+	return len(w.Events) == 0 || slices.Contains(w.Events, eventType)
+}
+
+// WebhookInfo é um webhook como exposto pela API (sem o secret).
+type WebhookInfo struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Signed bool     `json:"signed"`
+	Events []string `json:"events"`
+}
+
+// Estados de uma entrega
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery é o registro de uma entrega de evento a um webhook.
+type WebhookDelivery struct {
+	ID         string     `json:"id"`
+	WebhookID  string     `json:"webhook_id"`
+	EventID    int64      `json:"event_id"`
+	Event      string     `json:"event"`
+	Meeting    int        `json:"numero_reuniao"`
+	Status     string     `json:"status"`
+	Attempts   int        `json:"attempts"`
+	StatusCode int        `json:"status_code,omitempty"` // Da última tentativa
+	Error      string     `json:"error,omitempty"`       // Da última tentativa
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// webhooksFilename permite trocar o arquivo pela variável WEBHOOKS_CONFIG.
+func webhooksFilename() string {
+	if name := os.Getenv("WEBHOOKS_CONFIG"); name != "" {
+		return name
+	}
+	return defaultWebhooksFilename
+}
+
+func webhookMaxAttempts() int {
+	if val, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS")); err == nil && val > 0 {
+		return val
+	}
+	return defaultWebhookMaxAttempts
+}
+
+// loadWebhooks lê a lista de webhooks. Arquivo inexistente significa nenhum webhook.
+func loadWebhooks(filename string) ([]Webhook, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var hooks []Webhook
+	if err := json.Unmarshal(data, &hooks); err != nil {
+		return nil, fmt.Errorf("JSON inválido: %w", err)
+	}
+	seen := make(map[string]bool)
+	for i, h := range hooks {
+		if h.ID == "" {
+			return nil, fmt.Errorf("webhook %d sem id", i+1)
+		}
+		if seen[h.ID] {
+			return nil, fmt.Errorf("id de webhook repetido: %s", h.ID)
+		}
+		seen[h.ID] = true
+		if h.URL == "" {
+			return nil, fmt.Errorf("webhook %s sem url", h.ID)
+		}
+		for _, ev := range h.Events {
+			if !slices.Contains(webhookEventTypes, ev) {
+				return nil, fmt.Errorf("webhook %s: evento desconhecido %q", h.ID, ev)
+			}
+		}
+	}
+	return hooks, nil
+}
+
+// signWebhookPayload calcula a assinatura enviada em X-Copom-Signature.
+func signWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookDispatcher assina o eventBus e entrega cada evento em uma goroutine própria por
+// webhook, com as tentativas registradas no log de entregas.
+type webhookDispatcher struct {
+	hooks       []Webhook
+	client      *http.Client
+	maxAttempts int
+
+	mu         sync.Mutex
+	deliveries []WebhookDelivery // mais antiga primeiro
+	pending    sync.WaitGroup    // entregas em andamento (ver Wait)
+}
+
+func newWebhookDispatcher(hooks []Webhook) *webhookDispatcher {
+	return &webhookDispatcher{
+		hooks:       hooks,
+		client:      &http.Client{Timeout: webhookTimeout},
+		maxAttempts: webhookMaxAttempts(),
+	}
+}
+
+// Handle é o assinante do eventBus.
+func (d *webhookDispatcher) Handle(ev Event) {
+	for _, hook := range d.hooks {
+		if !hook.wants(ev.Type) {
+			continue
+		}
+		delivery := WebhookDelivery{
+			ID:        newJobID(),
+			WebhookID: hook.ID,
+			EventID:   ev.ID,
+			Event:     ev.Type,
+			Status:    DeliveryPending,
+			CreatedAt: time.Now(),
+		}
+		if meeting, ok := ev.Data.(MeetingEvent); ok {
+			delivery.Meeting = meeting.NumeroReuniao
+		}
+		d.record(delivery)
+		d.pending.Add(1)
+		go func() {
+			defer d.pending.Done()
+			d.deliver(context.Background(), hook, ev, delivery.ID)
+		}()
+	}
+}
+
+func (d *webhookDispatcher) deliver(ctx context.Context, hook Webhook, ev Event, deliveryID string) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		d.finish(deliveryID, DeliveryFailed)
+		log.Printf("[webhook %s] Erro ao serializar evento %d: %v", hook.ID, ev.ID, err)
+		return
+	}
+
+	backoff := webhookInitialBackoff
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		code, err := d.post(ctx, hook, ev, deliveryID, body)
+		d.update(deliveryID, func(dl *WebhookDelivery) {
+			dl.Attempts, dl.StatusCode, dl.Error = attempt, code, ""
+			if err != nil {
+				dl.Error = err.Error()
+			}
+		})
+		if err == nil {
+			d.finish(deliveryID, DeliverySucceeded)
+			log.Printf("[webhook %s] Evento %d (%s) entregue (HTTP %d).", hook.ID, ev.ID, ev.Type, code)
+			return
+		}
+		log.Printf("[webhook %s] Tentativa %d/%d do evento %d falhou: %v", hook.ID, attempt, d.maxAttempts, ev.ID, err)
+		if attempt < d.maxAttempts {
+			if sleepContext(ctx, backoff) != nil {
+				break
+			}
+			backoff *= 2
+		}
+	}
+	d.finish(deliveryID, DeliveryFailed)
+}
+
+func (d *webhookDispatcher) post(ctx context.Context, hook Webhook, ev Event, deliveryID string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "copom-crawler-webhook")
+	req.Header.Set("X-Copom-Event", ev.Type)
+	req.Header.Set("X-Copom-Delivery", deliveryID)
+	req.Header.Set("X-Copom-Timestamp", timestamp)
+	if hook.Secret != "" {
+		req.Header.Set("X-Copom-Signature", signWebhookPayload(hook.Secret, timestamp, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("resposta HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *webhookDispatcher) record(delivery WebhookDelivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deliveries = append(d.deliveries, delivery)
+	if excess := len(d.deliveries) - maxWebhookDeliveries; excess > 0 {
+		d.deliveries = slices.Delete(d.deliveries, 0, excess)
+	}
+}
+
+func (d *webhookDispatcher) update(id string, fn func(dl *WebhookDelivery)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := range d.deliveries {
+		if d.deliveries[i].ID == id {
+			fn(&d.deliveries[i])
+			return
+		}
+	}
+}
+
+func (d *webhookDispatcher) finish(id, status string) {
+	d.update(id, func(dl *WebhookDelivery) {
+		now := time.Now()
+		dl.Status, dl.FinishedAt = status, &now
+	})
+}
+
+// Wait espera as entregas em andamento terminarem (com sucesso ou esgotadas as tentativas).
+// Usado pelos modos de linha de comando antes de encerrar o processo.
+func (d *webhookDispatcher) Wait() {
+	d.pending.Wait()
+}
+
+// Webhooks lista os webhooks configurados, sem os secrets.
+func (d *webhookDispatcher) Webhooks() []WebhookInfo {
+	infos := make([]WebhookInfo, 0, len(d.hooks))
+	for _, h := range d.hooks {
+		events := h.Events
+		if len(events) == 0 {
+			events = webhookEventTypes
+		}
+		infos = append(infos, WebhookInfo{ID: h.ID, URL: h.URL, Signed: h.Secret != "", Events: events})
+	}
+	return infos
+}
+
+// Deliveries retorna as entregas da mais recente para a mais antiga, opcionalmente só as
+// de um webhook e/ou com um status.
+func (d *webhookDispatcher) Deliveries(webhookID, status string) []WebhookDelivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]WebhookDelivery, 0, len(d.deliveries))
+	for i := len(d.deliveries) - 1; i >= 0; i-- {
+		dl := d.deliveries[i]
+		if (webhookID == "" || dl.WebhookID == webhookID) && (status == "" || dl.Status == status) {
+			out = append(out, dl)
+		}
+	}
+	return out
+}